@@ -2,19 +2,27 @@ package account
 
 import (
 	"bufio"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 
 	// "github.com/cosmos/cosmos-sdk/codec"
+	dkeyring "github.com/99designs/keyring"
 	"github.com/cosmos/cosmos-sdk/codec/types"
 	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 )
 
 type Registry struct {
 	homePath           string
 	keyringServiceName string
 	keyringBackend     KeyringBackend
+	passphrase         PassphraseProvider
 
 	Keyring keyring.Keyring
 }
@@ -33,6 +41,13 @@ const (
 
 	// KeyringMemory is in memory keyring backend, your keys will be stored in application memory.
 	KeyringMemory KeyringBackend = "memory"
+
+	// KeyringFile is the encrypted file keyring backend. With this backend, your keys
+	// will be stored encrypted under your app's data dir and unlocked with a passphrase,
+	// see WithPassphraseProvider.
+	KeyringFile KeyringBackend = "file"
+
+	AccountPrefixCosmos = "akash"
 )
 
 // KeyringHome used to store account related data.
@@ -50,8 +65,13 @@ func New(options ...Option) (Registry, error) {
 		apply(&r)
 	}
 
-	var err error
-	inBuf := bufio.NewReader(os.Stdin)
+	var (
+		err   error
+		inBuf io.Reader = bufio.NewReader(os.Stdin)
+	)
+	if r.passphrase != nil {
+		inBuf = passphraseReader{r.passphrase}
+	}
 	interfaceRegistry := types.NewInterfaceRegistry()
 	cryptocodec.RegisterInterfaces(interfaceRegistry)
 	// cdc := codec.NewProtoCodec(interfaceRegistry)
@@ -85,3 +105,83 @@ func WithHome(path string) Option {
 		c.homePath = path
 	}
 }
+
+// WithPassphraseProvider sets the provider of the keyring passphrase, used
+// instead of stdin to unlock encrypted backends. The keyring reads it when it
+// first needs the passphrase.
+func WithPassphraseProvider(provider PassphraseProvider) Option {
+	return func(c *Registry) {
+		c.passphrase = provider
+	}
+}
+
+type Account struct {
+	// Name of the account.
+	Name string
+
+	// Record holds additional info about the account.
+	// It is nil for watch-only accounts, see NewWatchOnly.
+	Info keyring.Info
+
+	// watchAddress and watchPubKey identify a watch-only account.
+	watchAddress sdktypes.AccAddress
+	watchPubKey  cryptotypes.PubKey
+}
+
+func (r Registry) GetByName(name string) (Account, error) {
+	info, err := r.Keyring.Key(name)
+	if errors.Is(err, dkeyring.ErrKeyNotFound) || errors.Is(err, sdkerrors.ErrKeyNotFound) {
+		return Account{}, &AccountDoesNotExistError{name}
+	}
+	if err != nil {
+		return Account{}, err
+	}
+
+	return Account{
+		Name: name,
+		Info: info,
+	}, nil
+}
+
+// GetByAddress returns an account by its bech32 address, whatever its prefix.
+func (r Registry) GetByAddress(address string) (Account, error) {
+	_, bz, err := bech32.DecodeAndConvert(address)
+	if err != nil {
+		return Account{}, err
+	}
+
+	info, err := r.Keyring.KeyByAddress(sdktypes.AccAddress(bz))
+	if errors.Is(err, dkeyring.ErrKeyNotFound) || errors.Is(err, sdkerrors.ErrKeyNotFound) {
+		return Account{}, &AccountDoesNotExistError{address}
+	}
+	if err != nil {
+		return Account{}, err
+	}
+	// the keyring returns no info and no error when the address index points
+	// to an empty entry.
+	if info == nil {
+		return Account{}, &AccountDoesNotExistError{address}
+	}
+
+	return Account{
+		Name: info.GetName(),
+		Info: info,
+	}, nil
+}
+
+type AccountDoesNotExistError struct {
+	Name string
+}
+
+func (e *AccountDoesNotExistError) Error() string {
+	return fmt.Sprintf("account %q does not exist", e.Name)
+}
+
+// Address returns the address of the account from given prefix.
+func (a Account) Address(accPrefix string) (string, error) {
+	if accPrefix == "" {
+		accPrefix = AccountPrefixCosmos
+	}
+
+	return bech32.ConvertAndEncode(accPrefix, a.AccAddress())
+}
@@ -0,0 +1,244 @@
+// Package address converts account, validator and consensus addresses
+// between their bech32, bytes and hex forms for a given chain prefix.
+// Unlike the Cosmos SDK address types, it never reads nor writes the SDK
+// global config, so codecs of several chains can be used in one process.
+package address
+
+import (
+	"encoding/hex"
+	"strings"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultAccountPrefix is the bech32 account prefix of Akash.
+	DefaultAccountPrefix = "akash"
+
+	defaultCacheSize = 1024
+
+	// maxAddrLen is the maximum length of an address, as in the SDK.
+	maxAddrLen = 255
+)
+
+// Option configures your codec.
+type Option func(*Codec)
+
+// WithValidatorPrefix sets the bech32 prefix of validator operator addresses,
+// the account prefix followed by "valoper" by default.
+func WithValidatorPrefix(prefix string) Option {
+	return func(c *Codec) {
+		c.validatorPrefix = prefix
+	}
+}
+
+// WithConsensusPrefix sets the bech32 prefix of validator consensus addresses,
+// the account prefix followed by "valcons" by default.
+func WithConsensusPrefix(prefix string) Option {
+	return func(c *Codec) {
+		c.consensusPrefix = prefix
+	}
+}
+
+// WithCacheSize sets the number of account addresses whose bech32 form is
+// cached, 0 disables the cache.
+func WithCacheSize(size int) Option {
+	return func(c *Codec) {
+		c.cacheSize = size
+	}
+}
+
+// Codec converts the addresses of one chain. It is safe for concurrent use.
+type Codec struct {
+	accountPrefix   string
+	validatorPrefix string
+	consensusPrefix string
+
+	cacheSize int
+	cache     *lru.Cache
+}
+
+// NewCodec creates a new codec for the account prefix.
+func NewCodec(accountPrefix string, options ...Option) (Codec, error) {
+	if accountPrefix == "" {
+		return Codec{}, errors.New("empty account prefix")
+	}
+
+	c := Codec{
+		accountPrefix:   accountPrefix,
+		validatorPrefix: accountPrefix + sdktypes.PrefixValidator + sdktypes.PrefixOperator,
+		consensusPrefix: accountPrefix + sdktypes.PrefixValidator + sdktypes.PrefixConsensus,
+		cacheSize:       defaultCacheSize,
+	}
+
+	for _, apply := range options {
+		apply(&c)
+	}
+
+	if c.cacheSize > 0 {
+		cache, err := lru.New(c.cacheSize)
+		if err != nil {
+			return Codec{}, errors.WithStack(err)
+		}
+		c.cache = cache
+	}
+
+	return c, nil
+}
+
+// AccountPrefix returns the bech32 prefix of account addresses.
+func (c Codec) AccountPrefix() string {
+	return c.accountPrefix
+}
+
+// AccountPubPrefix returns the bech32 prefix of account public keys.
+func (c Codec) AccountPubPrefix() string {
+	return c.accountPrefix + sdktypes.PrefixPublic
+}
+
+// ValidatorPrefix returns the bech32 prefix of validator operator addresses.
+func (c Codec) ValidatorPrefix() string {
+	return c.validatorPrefix
+}
+
+// ConsensusPrefix returns the bech32 prefix of validator consensus addresses.
+func (c Codec) ConsensusPrefix() string {
+	return c.consensusPrefix
+}
+
+// BytesToString encodes an account address to bech32.
+func (c Codec) BytesToString(bz []byte) (string, error) {
+	if len(bz) == 0 {
+		return "", nil
+	}
+
+	if c.cache != nil {
+		if s, ok := c.cache.Get(string(bz)); ok {
+			return s.(string), nil
+		}
+	}
+
+	s, err := encode(c.accountPrefix, bz)
+	if err != nil {
+		return "", err
+	}
+
+	if c.cache != nil {
+		c.cache.Add(string(bz), s)
+	}
+
+	return s, nil
+}
+
+// StringToBytes decodes a bech32 account address.
+func (c Codec) StringToBytes(s string) ([]byte, error) {
+	return decode(c.accountPrefix, s)
+}
+
+// String encodes an account address to bech32, it returns an empty string
+// for empty or invalid addresses.
+func (c Codec) String(addr sdktypes.AccAddress) string {
+	s, _ := c.BytesToString(addr)
+	return s
+}
+
+// AccAddress decodes a bech32 account address.
+func (c Codec) AccAddress(s string) (sdktypes.AccAddress, error) {
+	return c.StringToBytes(s)
+}
+
+// ValAddressToString encodes a validator operator address to bech32.
+func (c Codec) ValAddressToString(addr sdktypes.ValAddress) (string, error) {
+	if addr.Empty() {
+		return "", nil
+	}
+	return encode(c.validatorPrefix, addr)
+}
+
+// ValAddress decodes a bech32 validator operator address.
+func (c Codec) ValAddress(s string) (sdktypes.ValAddress, error) {
+	return decode(c.validatorPrefix, s)
+}
+
+// ConsAddressToString encodes a validator consensus address to bech32.
+func (c Codec) ConsAddressToString(addr sdktypes.ConsAddress) (string, error) {
+	if addr.Empty() {
+		return "", nil
+	}
+	return encode(c.consensusPrefix, addr)
+}
+
+// ConsAddress decodes a bech32 validator consensus address.
+func (c Codec) ConsAddress(s string) (sdktypes.ConsAddress, error) {
+	return decode(c.consensusPrefix, s)
+}
+
+// HexToString encodes a hex account address to bech32.
+func (c Codec) HexToString(s string) (string, error) {
+	bz, err := HexToBytes(s)
+	if err != nil {
+		return "", err
+	}
+	return c.BytesToString(bz)
+}
+
+// StringToHex decodes a bech32 account address to its upper case hex form.
+func (c Codec) StringToHex(s string) (string, error) {
+	bz, err := c.StringToBytes(s)
+	if err != nil {
+		return "", err
+	}
+	return BytesToHex(bz), nil
+}
+
+// BytesToHex returns the upper case hex form of an address, as displayed by
+// Tendermint.
+func BytesToHex(bz []byte) string {
+	return strings.ToUpper(hex.EncodeToString(bz))
+}
+
+// HexToBytes decodes a hex address, in upper or lower case.
+func HexToBytes(s string) ([]byte, error) {
+	bz, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decoding hex address %q", s)
+	}
+	return bz, verify(bz)
+}
+
+func encode(prefix string, bz []byte) (string, error) {
+	if err := verify(bz); err != nil {
+		return "", err
+	}
+	s, err := bech32.ConvertAndEncode(prefix, bz)
+	return s, errors.Wrapf(err, "encoding address with prefix %q", prefix)
+}
+
+func decode(prefix, s string) ([]byte, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, errors.New("empty address string is not allowed")
+	}
+
+	hrp, bz, err := bech32.DecodeAndConvert(s)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decoding address %q", s)
+	}
+	if hrp != prefix {
+		return nil, errors.Errorf("invalid bech32 prefix of address %q, expected %q, got %q", s, prefix, hrp)
+	}
+
+	return bz, verify(bz)
+}
+
+func verify(bz []byte) error {
+	switch {
+	case len(bz) == 0:
+		return errors.New("empty address")
+	case len(bz) > maxAddrLen:
+		return errors.Errorf("address max length is %d, got %d", maxAddrLen, len(bz))
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package address
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+var (
+	addr20 = []byte("addr________________")
+	addr32 = bytes.Repeat([]byte{0xab}, 32)
+)
+
+func newTestCodec(t *testing.T, prefix string, options ...Option) Codec {
+	t.Helper()
+
+	c, err := NewCodec(prefix, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestRoundTrip(t *testing.T) {
+	codecs := map[string]Codec{
+		"cached":   newTestCodec(t, DefaultAccountPrefix),
+		"uncached": newTestCodec(t, DefaultAccountPrefix, WithCacheSize(0)),
+		"custom prefixes": newTestCodec(t, "cosmos",
+			WithValidatorPrefix("cosmosop"),
+			WithConsensusPrefix("cosmoscons")),
+	}
+
+	for name, c := range codecs {
+		t.Run(name, func(t *testing.T) {
+			for _, bz := range [][]byte{addr20, addr32} {
+				// twice, to go through the cache.
+				for i := 0; i < 2; i++ {
+					s, err := c.BytesToString(bz)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !strings.HasPrefix(s, c.AccountPrefix()+"1") {
+						t.Errorf("BytesToString() = %q, want prefix %q", s, c.AccountPrefix())
+					}
+					got, err := c.StringToBytes(s)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !bytes.Equal(got, bz) {
+						t.Errorf("StringToBytes(BytesToString(%X)) = %X", bz, got)
+					}
+				}
+
+				s, err := c.ValAddressToString(bz)
+				if err != nil {
+					t.Fatal(err)
+				}
+				val, err := c.ValAddress(s)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !strings.HasPrefix(s, c.ValidatorPrefix()+"1") || !bytes.Equal(val, bz) {
+					t.Errorf("ValAddress(ValAddressToString(%X)) = %X, %q", bz, val, s)
+				}
+
+				s, err = c.ConsAddressToString(bz)
+				if err != nil {
+					t.Fatal(err)
+				}
+				cons, err := c.ConsAddress(s)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !strings.HasPrefix(s, c.ConsensusPrefix()+"1") || !bytes.Equal(cons, bz) {
+					t.Errorf("ConsAddress(ConsAddressToString(%X)) = %X, %q", bz, cons, s)
+				}
+
+				s, err = c.HexToString(strings.ToLower(BytesToHex(bz)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				hex, err := c.StringToHex(s)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if hex != BytesToHex(bz) {
+					t.Errorf("StringToHex(HexToString(%X)) = %s", bz, hex)
+				}
+			}
+		})
+	}
+}
+
+func TestEmptyAddress(t *testing.T) {
+	c := newTestCodec(t, DefaultAccountPrefix)
+
+	if s, err := c.BytesToString(nil); s != "" || err != nil {
+		t.Errorf("BytesToString(nil) = %q, %v, want an empty string", s, err)
+	}
+	if s := c.String(sdktypes.AccAddress{}); s != "" {
+		t.Errorf("String() = %q, want an empty string", s)
+	}
+	if _, err := c.StringToBytes(" "); err == nil {
+		t.Error("StringToBytes() of a blank string succeeded")
+	}
+	if _, err := c.BytesToString(make([]byte, maxAddrLen+1)); err == nil {
+		t.Error("BytesToString() of a too long address succeeded")
+	}
+}
+
+func TestWrongPrefix(t *testing.T) {
+	akash := newTestCodec(t, DefaultAccountPrefix)
+	cosmos := newTestCodec(t, "cosmos")
+
+	acc, err := akash.BytesToString(addr20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	val, err := akash.ValAddressToString(addr20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cons, err := akash.ConsAddressToString(addr20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherChain, err := cosmos.BytesToString(addr20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		decode func(string) ([]byte, error)
+		s      string
+	}{
+		{"account from another chain", akash.StringToBytes, otherChain},
+		{"validator as account", akash.StringToBytes, val},
+		{"consensus as account", akash.StringToBytes, cons},
+		{"account as validator", func(s string) ([]byte, error) { return akash.ValAddress(s) }, acc},
+		{"consensus as validator", func(s string) ([]byte, error) { return akash.ValAddress(s) }, cons},
+		{"account as consensus", func(s string) ([]byte, error) { return akash.ConsAddress(s) }, acc},
+		{"account to hex of another chain", func(s string) ([]byte, error) {
+			_, err := cosmos.StringToHex(s)
+			return nil, err
+		}, acc},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.decode(tt.s)
+			if err == nil || !strings.Contains(err.Error(), "invalid bech32 prefix") {
+				t.Errorf("decoding %q = %v, want an invalid prefix error", tt.s, err)
+			}
+		})
+	}
+}
@@ -2,12 +2,13 @@ package client
 
 import (
 	"context"
+	"errors"
 	"io"
 	"os"
 	"path/filepath"
-	"sync"
 
 	"akashrpcclient/account"
+	"akashrpcclient/address"
 
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/flags"
@@ -16,6 +17,7 @@ import (
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
 	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
 	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
 	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
@@ -104,17 +106,23 @@ func New(ctx context.Context, options ...Option) (Client, error) {
 		account.WithKeyringServiceName(c.keyringServiceName),
 		account.WithKeyringBackend(c.keyringBackend),
 		account.WithHome(c.keyringDir),
+		account.WithPassphraseProvider(c.keyringPassphrase),
 	)
 	if err != nil {
 		return Client{}, err
 	}
 
-	c.context = c.newContext()
-	c.TxFactory = newFactory(c.context)
+	if c.addressCodec, err = address.NewCodec(c.addressPrefix); err != nil {
+		return Client{}, err
+	}
 
 	if c.accountRetriever == nil {
-		c.accountRetriever = authtypes.AccountRetriever{}
+		c.accountRetriever = accountRetriever{c.addressCodec}
 	}
+
+	c.context = c.newContext()
+	c.TxFactory = newFactory(c.context)
+
 	if c.bankQueryClient == nil {
 		c.bankQueryClient = banktypes.NewQueryClient(c.context)
 	}
@@ -124,9 +132,6 @@ func New(ctx context.Context, options ...Option) (Client, error) {
 	if c.signer == nil {
 		c.signer = signer{}
 	}
-	// set address prefix in SDK global config
-	c.SetConfigAddressPrefix()
-
 	return c, nil
 }
 
@@ -149,6 +154,7 @@ type Client struct {
 	signer           Signer
 
 	addressPrefix string
+	addressCodec  address.Codec
 
 	nodeAddress string
 	out         io.Writer
@@ -158,6 +164,7 @@ type Client struct {
 	keyringServiceName string
 	keyringBackend     account.KeyringBackend
 	keyringDir         string
+	keyringPassphrase  account.PassphraseProvider
 
 	gas           string
 	gasPrices     string
@@ -172,6 +179,28 @@ func WithAddressPrefix(prefix string) Option {
 	}
 }
 
+// WithKeyringBackend sets the backend of the keyring.
+func WithKeyringBackend(backend account.KeyringBackend) Option {
+	return func(c *Client) {
+		c.keyringBackend = backend
+	}
+}
+
+// WithKeyringDir sets the directory of the keyring, the home directory by default.
+func WithKeyringDir(dir string) Option {
+	return func(c *Client) {
+		c.keyringDir = dir
+	}
+}
+
+// WithKeyringPassphraseProvider sets the provider of the passphrase unlocking
+// encrypted keyring backends.
+func WithKeyringPassphraseProvider(provider account.PassphraseProvider) Option {
+	return func(c *Client) {
+		c.keyringPassphrase = provider
+	}
+}
+
 func (c Client) newContext() client.Context {
 	var (
 		amino             = codec.NewLegacyAmino()
@@ -216,17 +245,36 @@ func newFactory(clientCtx client.Context) tx.Factory {
 		WithTxConfig(clientCtx.TxConfig)
 }
 
-// protects sdktypes.Config.
-var mconf sync.Mutex
-
 // SetConfigAddressPrefix sets the account prefix in the SDK global config.
+// The client itself never reads the global config, which is only needed by
+// SDK helpers such as sdktypes.AccAddress.String. Processes using a single
+// chain may call it once at start-up; it is not safe for concurrent use.
 func (c Client) SetConfigAddressPrefix() {
-	mconf.Lock()
-	defer mconf.Unlock()
 	config := sdktypes.GetConfig()
-	config.SetBech32PrefixForAccount(c.addressPrefix, c.addressPrefix+"pub")
+	config.SetBech32PrefixForAccount(c.addressCodec.AccountPrefix(), c.addressCodec.AccountPubPrefix())
+}
+
+// AddressCodec returns the address codec of the chain of the client.
+func (c Client) AddressCodec() address.Codec {
+	return c.addressCodec
 }
 
 func (c Client) Context() client.Context {
 	return c.context
 }
+
+// Account returns an account of the keyring by its name or its address.
+func (c Client) Account(nameOrAddress string) (account.Account, error) {
+	acc, err := c.AccountRegistry.GetByName(nameOrAddress)
+	var notExistErr *account.AccountDoesNotExistError
+	if !errors.As(err, &notExistErr) {
+		return acc, err
+	}
+
+	// not a known name, look it up as an address if it is one.
+	if _, _, decodeErr := bech32.DecodeAndConvert(nameOrAddress); decodeErr != nil {
+		return account.Account{}, err
+	}
+
+	return c.AccountRegistry.GetByAddress(nameOrAddress)
+}
@@ -0,0 +1,29 @@
+// Package deployment queries Akash deployments.
+package deployment
+
+import (
+	"akashrpcclient/client"
+
+	"github.com/akash-network/node/x/deployment/types/v1beta2"
+	"github.com/cosmos/cosmos-sdk/types/query"
+)
+
+// Service queries the deployments of the chain of a client.
+type Service struct {
+	queryClient v1beta2.QueryClient
+}
+
+// New creates a new deployment service using c to query.
+func New(c client.Client) Service {
+	return Service{
+		queryClient: v1beta2.NewQueryClient(c.Context()),
+	}
+}
+
+// nextPage returns the request of the page following resp, nil on the last page.
+func nextPage(resp *query.PageResponse) *query.PageRequest {
+	if resp == nil || len(resp.NextKey) == 0 {
+		return nil
+	}
+	return &query.PageRequest{Key: resp.NextKey}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"akashrpcclient/client"
+	"akashrpcclient/deployment"
 	"context"
 	"fmt"
 	"log"
@@ -22,15 +23,22 @@ func main() {
 	// Instantiate a query client
 	queryClient := types.NewQueryClient(client.Context())
 
-	// // Query the blockchain using the client's `Deployments` method for a return of all deployments
-	// queryResp, err := queryClient.Deployments(ctx, &types.QueryDeploymentsRequest{})
-	// if err != nil {
-	// 	log.Fatal(err)
-	// }
-
-	// // Print response from querying all deployments on the blockchain
-	// fmt.Print("\n\nAll Akash deployments on the blockchain:\n\n")
-	// fmt.Println(queryResp)
+	// List the active deployments of an owner, following the pagination of
+	// the `Deployments` query page after page
+	deployments := deployment.New(client).List(ctx,
+		deployment.WithOwner("akash1f53fp8kk470f7k26yr5gztd9npzpczqv4ufud7"),
+		deployment.WithState(types.DeploymentActive),
+	)
+	defer deployments.Close()
+
+	// Print the deployments as they are fetched from the blockchain
+	fmt.Print("\n\nActive Akash deployments of the owner on the blockchain:\n\n")
+	for deployments.Next() {
+		fmt.Println(deployments.Deployment().Deployment)
+	}
+	if err := deployments.Err(); err != nil {
+		log.Fatal(err)
+	}
 
 	// Query the blockchain using the client's `Deployment` method for a return of a specific deployment
 	deploymentid := types.DeploymentID{
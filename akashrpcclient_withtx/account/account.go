@@ -4,14 +4,15 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io"
 	"os"
-	"reflect"
 
 	// "github.com/cosmos/cosmos-sdk/codec"
 	dkeyring "github.com/99designs/keyring"
 	"github.com/cosmos/cosmos-sdk/codec/types"
 	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/bech32"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
@@ -21,6 +22,7 @@ type Registry struct {
 	homePath           string
 	keyringServiceName string
 	keyringBackend     KeyringBackend
+	passphrase         PassphraseProvider
 
 	Keyring keyring.Keyring
 }
@@ -48,6 +50,11 @@ const (
 	// KeyringMemory is in memory keyring backend, your keys will be stored in application memory.
 	KeyringMemory KeyringBackend = "memory"
 
+	// KeyringFile is the encrypted file keyring backend. With this backend, your keys
+	// will be stored encrypted under your app's data dir and unlocked with a passphrase,
+	// see WithPassphraseProvider.
+	KeyringFile KeyringBackend = "file"
+
 	AccountPrefixCosmos = "akash"
 )
 
@@ -66,8 +73,13 @@ func New(options ...Option) (Registry, error) {
 		apply(&r)
 	}
 
-	var err error
-	inBuf := bufio.NewReader(os.Stdin)
+	var (
+		err   error
+		inBuf io.Reader = bufio.NewReader(os.Stdin)
+	)
+	if r.passphrase != nil {
+		inBuf = passphraseReader{r.passphrase}
+	}
 	interfaceRegistry := types.NewInterfaceRegistry()
 	cryptocodec.RegisterInterfaces(interfaceRegistry)
 	r.Keyring, err = keyring.New(r.keyringServiceName, string(r.keyringBackend), r.homePath, inBuf)
@@ -76,6 +88,10 @@ func New(options ...Option) (Registry, error) {
 		return Registry{}, err
 	}
 
+	if err := r.recoverRenames(); err != nil {
+		return Registry{}, err
+	}
+
 	return r, nil
 }
 
@@ -100,6 +116,15 @@ func WithHome(path string) Option {
 	}
 }
 
+// WithPassphraseProvider sets the provider of the keyring passphrase, used
+// instead of stdin to unlock encrypted backends. The keyring reads it when it
+// first needs the passphrase.
+func WithPassphraseProvider(provider PassphraseProvider) Option {
+	return func(c *Registry) {
+		c.passphrase = provider
+	}
+}
+
 type Account struct {
 	// Name of the account.
 	Name string
@@ -108,12 +133,17 @@ type Account struct {
 	// Record *keyring.Record
 
 	// Record holds additional info about the account.
+	// It is nil for watch-only accounts, see NewWatchOnly.
 	Info keyring.Info
+
+	// watchAddress and watchPubKey identify a watch-only account.
+	watchAddress sdktypes.AccAddress
+	watchPubKey  cryptotypes.PubKey
 }
 
 func (r Registry) GetByName(name string) (Account, error) {
 	info, err := r.Keyring.Key(name)
-	if errors.Is(err, dkeyring.ErrKeyNotFound) || errors.Is(err, sdkerrors.ErrKeyNotFound) {
+	if isKeyNotFound(err) {
 		return Account{}, &AccountDoesNotExistError{name}
 	}
 	if err != nil {
@@ -128,34 +158,37 @@ func (r Registry) GetByName(name string) (Account, error) {
 	return acc, nil
 }
 
-// GetByAddress returns an account by its address.
+// GetByAddress returns an account by its bech32 address, whatever its prefix.
 func (r Registry) GetByAddress(address string) (Account, error) {
-
-	sdkAddr, err := sdktypes.AccAddressFromBech32(address)
+	_, bz, err := bech32.DecodeAndConvert(address)
 	if err != nil {
 		return Account{}, err
 	}
 
-	info, err := r.Keyring.KeyByAddress(sdkAddr)
-
-	// Access the `name` field of the struct returned by the KeyByAddress function.
-	// The fields in this Cosmos SDK struct are not exported and thus must use reflect to access
-	v := reflect.ValueOf(info)
-	s := v.FieldByName("Name")
-	strName := fmt.Sprintf("%v", s.Interface())
-
-	if errors.Is(err, dkeyring.ErrKeyNotFound) || errors.Is(err, sdkerrors.ErrKeyNotFound) {
+	info, err := r.Keyring.KeyByAddress(sdktypes.AccAddress(bz))
+	if isKeyNotFound(err) {
 		return Account{}, &AccountDoesNotExistError{address}
 	}
 	if err != nil {
 		return Account{}, err
 	}
+	// the keyring returns no info and no error when the address index points
+	// to an empty entry.
+	if info == nil {
+		return Account{}, &AccountDoesNotExistError{address}
+	}
+
 	return Account{
-		Name: strName,
+		Name: info.GetName(),
 		Info: info,
 	}, nil
 }
 
+// isKeyNotFound reports whether err is the keyring error of a missing key.
+func isKeyNotFound(err error) bool {
+	return errors.Is(err, dkeyring.ErrKeyNotFound) || errors.Is(err, sdkerrors.ErrKeyNotFound)
+}
+
 type AccountDoesNotExistError struct {
 	Name string
 }
@@ -170,9 +203,7 @@ func (a Account) Address(accPrefix string) (string, error) {
 		accPrefix = AccountPrefixCosmos
 	}
 
-	pk := a.Info.GetPubKey()
-
-	return toBech32(accPrefix, pk.Address())
+	return toBech32(accPrefix, a.AccAddress())
 }
 
 func toBech32(prefix string, addr []byte) (string, error) {
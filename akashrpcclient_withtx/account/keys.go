@@ -0,0 +1,430 @@
+package account
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+// DefaultCoinType is the BIP44 coin type of Akash keys.
+const DefaultCoinType uint32 = sdktypes.CoinType
+
+// KeyOption configures the derivation of a key.
+type KeyOption func(*keyOptions)
+
+type keyOptions struct {
+	coinType        uint32
+	accountIndex    uint32
+	addressIndex    uint32
+	hdPath          string
+	bip39Passphrase string
+}
+
+// WithCoinType sets the BIP44 coin type, DefaultCoinType by default.
+func WithCoinType(coinType uint32) KeyOption {
+	return func(o *keyOptions) {
+		o.coinType = coinType
+	}
+}
+
+// WithAccountIndex sets the BIP44 account index.
+func WithAccountIndex(index uint32) KeyOption {
+	return func(o *keyOptions) {
+		o.accountIndex = index
+	}
+}
+
+// WithAddressIndex sets the BIP44 address index.
+func WithAddressIndex(index uint32) KeyOption {
+	return func(o *keyOptions) {
+		o.addressIndex = index
+	}
+}
+
+// WithHDPath sets the full HD path, it takes precedence over the coin type,
+// account and address index.
+func WithHDPath(path string) KeyOption {
+	return func(o *keyOptions) {
+		o.hdPath = path
+	}
+}
+
+// WithBIP39Passphrase sets the optional BIP39 passphrase of the mnemonic.
+func WithBIP39Passphrase(passphrase string) KeyOption {
+	return func(o *keyOptions) {
+		o.bip39Passphrase = passphrase
+	}
+}
+
+func newKeyOptions(options []KeyOption) keyOptions {
+	o := keyOptions{
+		coinType: DefaultCoinType,
+	}
+	for _, apply := range options {
+		apply(&o)
+	}
+	if o.hdPath == "" {
+		o.hdPath = hd.CreateHDPath(o.coinType, o.accountIndex, o.addressIndex).String()
+	}
+	return o
+}
+
+// Create creates a new account with a new BIP39 mnemonic, which is returned
+// along with the account.
+func (r Registry) Create(name string, options ...KeyOption) (Account, string, error) {
+	if err := r.ensureNotExists(name); err != nil {
+		return Account{}, "", err
+	}
+
+	o := newKeyOptions(options)
+	info, mnemonic, err := r.Keyring.NewMnemonic(name, keyring.English, o.hdPath, o.bip39Passphrase, hd.Secp256k1)
+	if err != nil {
+		return Account{}, "", err
+	}
+
+	return Account{
+		Name: name,
+		Info: info,
+	}, mnemonic, nil
+}
+
+// Import imports an account from its mnemonic.
+func (r Registry) Import(name, mnemonic string, options ...KeyOption) (Account, error) {
+	if err := r.ensureNotExists(name); err != nil {
+		return Account{}, err
+	}
+
+	o := newKeyOptions(options)
+	info, err := r.Keyring.NewAccount(name, mnemonic, o.bip39Passphrase, o.hdPath, hd.Secp256k1)
+	if err != nil {
+		return Account{}, err
+	}
+
+	return Account{
+		Name: name,
+		Info: info,
+	}, nil
+}
+
+// ImportArmor imports an account from an ASCII armored private key encrypted
+// with passphrase.
+func (r Registry) ImportArmor(name, armor, passphrase string) (Account, error) {
+	if err := r.ensureNotExists(name); err != nil {
+		return Account{}, err
+	}
+
+	if err := r.Keyring.ImportPrivKey(name, armor, passphrase); err != nil {
+		return Account{}, err
+	}
+
+	return r.GetByName(name)
+}
+
+// Export exports the private key of an account ASCII armored and encrypted
+// with passphrase.
+func (r Registry) Export(name, passphrase string) (string, error) {
+	if _, err := r.GetByName(name); err != nil {
+		return "", err
+	}
+
+	armor, err := r.Keyring.ExportPrivKeyArmor(name, passphrase)
+	return armor, err
+}
+
+// Delete deletes an account.
+func (r Registry) Delete(name string) error {
+	if _, err := r.GetByName(name); err != nil {
+		return err
+	}
+
+	return r.Keyring.Delete(name)
+}
+
+// Rename renames an account. Only accounts holding a private key in the
+// keyring can be renamed.
+//
+// The keyring has no rename and holds a single key per address, so the key is
+// exported, deleted and imported under newName. The exported key is written
+// to a rename journal in the keyring home first, from which New restores it
+// if the process stops before it is imported again. The journal is encrypted
+// with a passphrase derived from a throwaway key of the keyring, so it is as
+// safe as the keyring itself.
+func (r Registry) Rename(oldName, newName string) (Account, error) {
+	if _, err := r.GetByName(oldName); err != nil {
+		return Account{}, err
+	}
+	if err := r.ensureNotExists(newName); err != nil {
+		return Account{}, err
+	}
+
+	j, path, passphrase, err := r.beginRename(oldName, newName)
+	if err != nil {
+		return Account{}, err
+	}
+
+	if err := r.Keyring.Delete(oldName); err != nil {
+		r.endRename(j, path)
+		return Account{}, err
+	}
+
+	if err := r.Keyring.ImportPrivKey(newName, j.Armor, passphrase); err != nil {
+		if rerr := r.Keyring.ImportPrivKey(oldName, j.Armor, passphrase); rerr != nil {
+			return Account{}, fmt.Errorf("restoring account %q, it is restored by the next New from %s: %v: %w", oldName, path, rerr, err)
+		}
+		r.endRename(j, path)
+		return Account{}, err
+	}
+	r.endRename(j, path)
+
+	return r.GetByName(newName)
+}
+
+// renameJournal is the key of an account being renamed.
+type renameJournal struct {
+	OldName string `json:"old_name"`
+	NewName string `json:"new_name"`
+
+	// Key is the name of the keyring key the passphrase of Armor is derived
+	// from, see journalPassphrase.
+	Key   string `json:"key"`
+	Armor string `json:"armor"`
+}
+
+const (
+	renameJournalPattern   = "rename-*.json"
+	renameJournalKeyPrefix = "rename-journal-"
+)
+
+// beginRename exports the key of oldName and durably writes it to a new
+// journal file readable by the owner only, whose path is returned along with
+// the passphrase of the export. Nothing is written for the memory backend.
+func (r Registry) beginRename(oldName, newName string) (j renameJournal, path, passphrase string, err error) {
+	j = renameJournal{
+		OldName: oldName,
+		NewName: newName,
+	}
+
+	if r.keyringBackend == KeyringMemory {
+		if passphrase, err = randomPassphrase(); err != nil {
+			return renameJournal{}, "", "", err
+		}
+		j.Armor, err = r.Keyring.ExportPrivKeyArmor(oldName, passphrase)
+		if err != nil {
+			return renameJournal{}, "", "", fmt.Errorf("exporting account %q: %w", oldName, err)
+		}
+		return j, "", passphrase, nil
+	}
+
+	suffix, err := randomPassphrase()
+	if err != nil {
+		return renameJournal{}, "", "", err
+	}
+	j.Key = renameJournalKeyPrefix + suffix[:16]
+	if _, _, err := r.Keyring.NewMnemonic(j.Key, keyring.English, sdktypes.FullFundraiserPath, "", hd.Secp256k1); err != nil {
+		return renameJournal{}, "", "", fmt.Errorf("creating rename journal key: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			r.Keyring.Delete(j.Key)
+		}
+	}()
+
+	if passphrase, err = r.journalPassphrase(j.Key); err != nil {
+		return renameJournal{}, "", "", err
+	}
+	j.Armor, err = r.Keyring.ExportPrivKeyArmor(oldName, passphrase)
+	if err != nil {
+		return renameJournal{}, "", "", fmt.Errorf("exporting account %q: %w", oldName, err)
+	}
+
+	bz, err := json.Marshal(j)
+	if err != nil {
+		return renameJournal{}, "", "", err
+	}
+	if err := os.MkdirAll(r.homePath, 0o700); err != nil {
+		return renameJournal{}, "", "", fmt.Errorf("writing rename journal: %w", err)
+	}
+	f, err := os.CreateTemp(r.homePath, renameJournalPattern)
+	if err != nil {
+		return renameJournal{}, "", "", fmt.Errorf("writing rename journal: %w", err)
+	}
+
+	_, err = f.Write(bz)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return renameJournal{}, "", "", fmt.Errorf("writing rename journal %s: %w", f.Name(), err)
+	}
+
+	return j, f.Name(), passphrase, nil
+}
+
+// endRename deletes the journal key, then the journal of a completed rename.
+// A journal left behind without its key is removed by the next
+// recoverRenames.
+func (r Registry) endRename(j renameJournal, path string) {
+	if path == "" {
+		return
+	}
+	if err := r.Keyring.Delete(j.Key); err == nil || isKeyNotFound(err) {
+		os.Remove(path)
+	}
+}
+
+// journalPassphrase derives the passphrase of a rename journal from the
+// signature of the journal key, which is deterministic.
+func (r Registry) journalPassphrase(key string) (string, error) {
+	sig, _, err := r.Keyring.Sign(key, []byte("akashrpcclient rename journal"))
+	if err != nil {
+		return "", fmt.Errorf("deriving rename journal passphrase: %w", err)
+	}
+	sum := sha256.Sum256(sig)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// recoverRenames restores the keys of the renames interrupted between the
+// deletion and the import of the key, under their old name, or their new
+// name when the old one was taken since. It then deletes the journal keys
+// left behind.
+func (r Registry) recoverRenames() error {
+	if r.keyringBackend == KeyringMemory {
+		return nil
+	}
+
+	paths, err := filepath.Glob(filepath.Join(r.homePath, renameJournalPattern))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		if err := r.recoverRename(path); err != nil {
+			return err
+		}
+	}
+
+	infos, err := r.Keyring.List()
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		// the journal of the key was never written, or was recovered.
+		if strings.HasPrefix(info.GetName(), renameJournalKeyPrefix) {
+			if err := r.Keyring.Delete(info.GetName()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r Registry) recoverRename(path string) error {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading rename journal %s: %w", path, err)
+	}
+	var j renameJournal
+	if err := json.Unmarshal(bz, &j); err != nil {
+		return fmt.Errorf("reading rename journal %s: %w", path, err)
+	}
+
+	passphrase, err := r.journalPassphrase(j.Key)
+	if isKeyNotFound(err) {
+		// the journal key is deleted once the rename completed.
+		os.Remove(path)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	privKey, _, err := crypto.UnarmorDecryptPrivKey(j.Armor, passphrase)
+	if err != nil {
+		return fmt.Errorf("reading rename journal %s: %w", path, err)
+	}
+
+	info, err := r.Keyring.KeyByAddress(sdktypes.AccAddress(privKey.PubKey().Address()))
+	switch {
+	case err == nil && info != nil:
+		// the rename completed, or never deleted the key.
+	case err == nil || isKeyNotFound(err):
+		name := j.OldName
+		if err := r.ensureNotExists(name); err != nil {
+			name = j.NewName
+		}
+		if err := r.Keyring.ImportPrivKey(name, j.Armor, passphrase); err != nil {
+			return fmt.Errorf("restoring account %q from rename journal %s: %w", j.OldName, path, err)
+		}
+	default:
+		return fmt.Errorf("recovering rename journal %s: %w", path, err)
+	}
+
+	r.endRename(j, path)
+	return nil
+}
+
+// List returns every account of the keyring.
+func (r Registry) List() ([]Account, error) {
+	infos, err := r.Keyring.List()
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]Account, 0, len(infos))
+	for _, info := range infos {
+		if strings.HasPrefix(info.GetName(), renameJournalKeyPrefix) {
+			continue
+		}
+		accounts = append(accounts, Account{
+			Name: info.GetName(),
+			Info: info,
+		})
+	}
+
+	return accounts, nil
+}
+
+func (r Registry) ensureNotExists(name string) error {
+	_, err := r.GetByName(name)
+	var notExistErr *AccountDoesNotExistError
+	switch {
+	case err == nil:
+		return &AccountAlreadyExistsError{name}
+	case errors.As(err, &notExistErr):
+		return nil
+	default:
+		return err
+	}
+}
+
+func randomPassphrase() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+type AccountAlreadyExistsError struct {
+	Name string
+}
+
+func (e *AccountAlreadyExistsError) Error() string {
+	return fmt.Sprintf("account %q already exists", e.Name)
+}
@@ -0,0 +1,153 @@
+package account
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestRegistry(t *testing.T, home string) Registry {
+	t.Helper()
+	r, err := New(WithKeyringBackend(KeyringTest), WithHome(home))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// checkAccounts checks that the registry in home holds exactly the account
+// named name, with address, and that no rename journal is left.
+func checkAccounts(t *testing.T, home, name, address string) {
+	t.Helper()
+
+	r := newTestRegistry(t, home)
+	accounts, err := r.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 1 || accounts[0].Name != name {
+		t.Fatalf("accounts = %v, want only %q", accounts, name)
+	}
+	if got, _ := accounts[0].Address(""); got != address {
+		t.Errorf("address of %q = %s, want %s", name, got, address)
+	}
+
+	infos, err := r.Keyring.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 1 {
+		t.Errorf("keyring holds %d keys, want 1", len(infos))
+	}
+	journals, err := filepath.Glob(filepath.Join(home, renameJournalPattern))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(journals) != 0 {
+		t.Errorf("rename journals %v are left", journals)
+	}
+}
+
+func TestRename(t *testing.T) {
+	home := t.TempDir()
+	r := newTestRegistry(t, home)
+
+	acc, _, err := r.Create("old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, err := acc.Address("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Rename("old", "new"); err != nil {
+		t.Fatal(err)
+	}
+	checkAccounts(t, home, "new", address)
+}
+
+func TestRenameInterrupted(t *testing.T) {
+	tests := []struct {
+		name string
+
+		// interrupt runs the steps of the rename done before the interruption,
+		// after the journal is written.
+		interrupt func(t *testing.T, r Registry, j renameJournal, passphrase string)
+		want      string
+	}{
+		{
+			name:      "before delete",
+			interrupt: func(*testing.T, Registry, renameJournal, string) {},
+			want:      "old",
+		},
+		{
+			name: "after delete",
+			interrupt: func(t *testing.T, r Registry, _ renameJournal, _ string) {
+				if err := r.Keyring.Delete("old"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: "old",
+		},
+		{
+			name: "after import",
+			interrupt: func(t *testing.T, r Registry, j renameJournal, passphrase string) {
+				if err := r.Keyring.Delete("old"); err != nil {
+					t.Fatal(err)
+				}
+				if err := r.Keyring.ImportPrivKey("new", j.Armor, passphrase); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: "new",
+		},
+		{
+			name: "after journal key delete",
+			interrupt: func(t *testing.T, r Registry, j renameJournal, passphrase string) {
+				if err := r.Keyring.Delete("old"); err != nil {
+					t.Fatal(err)
+				}
+				if err := r.Keyring.ImportPrivKey("new", j.Armor, passphrase); err != nil {
+					t.Fatal(err)
+				}
+				if err := r.Keyring.Delete(j.Key); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: "new",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			r := newTestRegistry(t, home)
+
+			acc, _, err := r.Create("old")
+			if err != nil {
+				t.Fatal(err)
+			}
+			address, err := acc.Address("")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			j, path, passphrase, err := r.beginRename("old", "new")
+			if err != nil {
+				t.Fatal(err)
+			}
+			bz, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(bz), passphrase) {
+				t.Fatal("rename journal holds the passphrase of the key")
+			}
+
+			tt.interrupt(t, r, j, passphrase)
+			checkAccounts(t, home, tt.want, address)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package account
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// PassphraseProvider supplies the passphrase of encrypted keyring backends,
+// such as KeyringFile, so that they can be unlocked without a terminal.
+type PassphraseProvider interface {
+	Passphrase() (string, error)
+}
+
+// PassphraseFunc adapts a function to a PassphraseProvider.
+type PassphraseFunc func() (string, error)
+
+func (f PassphraseFunc) Passphrase() (string, error) {
+	return f()
+}
+
+// EnvPassphrase reads the passphrase from the environment variable name.
+func EnvPassphrase(name string) PassphraseProvider {
+	return PassphraseFunc(func() (string, error) {
+		pass, ok := os.LookupEnv(name)
+		if !ok {
+			return "", errors.Errorf("environment variable %q is not set", name)
+		}
+		return pass, nil
+	})
+}
+
+// FilePassphrase reads the passphrase from the first line of a secret file.
+func FilePassphrase(path string) PassphraseProvider {
+	return PassphraseFunc(func() (string, error) {
+		bz, err := os.ReadFile(path)
+		if err != nil {
+			return "", errors.Wrapf(err, "reading passphrase file %q", path)
+		}
+		return firstLine(string(bz)), nil
+	})
+}
+
+// FDPassphrase reads the passphrase from the first line of an inherited file
+// descriptor. The descriptor is read once and the passphrase kept in memory.
+func FDPassphrase(fd uintptr) PassphraseProvider {
+	var (
+		once sync.Once
+		pass string
+		err  error
+	)
+	return PassphraseFunc(func() (string, error) {
+		once.Do(func() {
+			f := os.NewFile(fd, "passphrase")
+			if f == nil {
+				err = errors.Errorf("invalid passphrase file descriptor %d", fd)
+				return
+			}
+			defer f.Close()
+
+			var bz []byte
+			if bz, err = io.ReadAll(f); err != nil {
+				err = errors.Wrapf(err, "reading passphrase file descriptor %d", fd)
+				return
+			}
+			pass = firstLine(string(bz))
+		})
+		return pass, err
+	})
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexAny(s, "\r\n"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// passphraseReader answers every keyring passphrase prompt with the passphrase
+// of the provider. Each Read returns exactly one line so that no passphrase is
+// lost when the keyring wraps the reader in a new bufio.Reader per prompt.
+type passphraseReader struct {
+	provider PassphraseProvider
+}
+
+func (r passphraseReader) Read(p []byte) (int, error) {
+	pass, err := r.provider.Passphrase()
+	if err != nil {
+		return 0, err
+	}
+
+	line := pass + "\n"
+	if len(p) < len(line) {
+		return 0, io.ErrShortBuffer
+	}
+
+	return copy(p, line), nil
+}
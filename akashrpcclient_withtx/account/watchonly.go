@@ -0,0 +1,54 @@
+package account
+
+import (
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/pkg/errors"
+)
+
+// NewWatchOnly returns an account for an address whose key is not in the
+// keyring. It can be used with every query and to build unsigned txs, the
+// optional pubKey is then set as the signer info of the tx.
+func NewWatchOnly(address sdktypes.AccAddress, pubKey cryptotypes.PubKey) Account {
+	return Account{
+		watchAddress: address,
+		watchPubKey:  pubKey,
+	}
+}
+
+// NewWatchOnlyFromBech32 returns a watch-only account for a bech32 address,
+// whatever its prefix.
+func NewWatchOnlyFromBech32(address string, pubKey cryptotypes.PubKey) (Account, error) {
+	_, bz, err := bech32.DecodeAndConvert(address)
+	if err != nil {
+		return Account{}, errors.Wrapf(err, "decoding address %q", address)
+	}
+	if pubKey != nil && !sdktypes.AccAddress(pubKey.Address()).Equals(sdktypes.AccAddress(bz)) {
+		return Account{}, errors.Errorf("public key does not match address %q", address)
+	}
+
+	return NewWatchOnly(bz, pubKey), nil
+}
+
+// IsWatchOnly reports whether the key of the account is not in the keyring.
+func (a Account) IsWatchOnly() bool {
+	return a.Info == nil
+}
+
+// AccAddress returns the address of the account.
+func (a Account) AccAddress() sdktypes.AccAddress {
+	if a.Info != nil {
+		return a.Info.GetAddress()
+	}
+	return a.watchAddress
+}
+
+// PubKey returns the public key of the account, which may be nil for
+// watch-only accounts.
+func (a Account) PubKey() cryptotypes.PubKey {
+	if a.Info != nil {
+		return a.Info.GetPubKey()
+	}
+	return a.watchPubKey
+}
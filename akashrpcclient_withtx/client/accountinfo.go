@@ -0,0 +1,272 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"akashrpcclient/account"
+
+	"github.com/akash-network/node/x/deployment/types/v1beta2"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	vestingexported "github.com/cosmos/cosmos-sdk/x/auth/vesting/exported"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	staking "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AccountInfo is an overview of an account, combining the keyring and the
+// on-chain state of the account.
+type AccountInfo struct {
+	// Name is the keyring name of the account, empty if it is not in the keyring.
+	Name string
+
+	// Address is the bech32 address of the account.
+	Address string
+
+	// PubKeyType is the type of the public key, empty if it is unknown.
+	PubKeyType string
+
+	// AccountNumber and Sequence are zero until the account exists on chain.
+	AccountNumber uint64
+	Sequence      uint64
+
+	// Balances are the bank balances of the account.
+	Balances sdktypes.Coins
+
+	// Vesting is the vesting schedule of vesting accounts, nil otherwise.
+	Vesting *VestingSchedule
+
+	// Delegated and Unbonding are the staked and unbonding bond denom coins.
+	Delegated sdktypes.Coin
+	Unbonding sdktypes.Coin
+
+	// Escrowed is the total escrowed in the open deployments of the account.
+	Escrowed sdktypes.DecCoins
+}
+
+// VestingSchedule describes the vesting of a vesting account.
+type VestingSchedule struct {
+	// Type is the vesting account type: continuous, delayed, periodic or
+	// permanent-locked.
+	Type string
+
+	OriginalVesting sdktypes.Coins
+	StartTime       time.Time
+	EndTime         time.Time
+
+	// Vested and Vesting are the vested and still vesting coins at the time
+	// of the query.
+	Vested  sdktypes.Coins
+	Vesting sdktypes.Coins
+
+	DelegatedFree    sdktypes.Coins
+	DelegatedVesting sdktypes.Coins
+
+	// Periods are the vesting periods of periodic vesting accounts.
+	Periods []VestingPeriod
+}
+
+// VestingPeriod is a period of a periodic vesting account.
+type VestingPeriod struct {
+	Length time.Duration
+	Amount sdktypes.Coins
+}
+
+// AccountInfo returns the overview of an account, by its keyring name or its
+// address. Addresses missing from the keyring are looked up as watch-only.
+func (c Client) AccountInfo(ctx context.Context, nameOrAddress string) (AccountInfo, error) {
+	acc, err := c.Account(nameOrAddress)
+	var notExistErr *account.AccountDoesNotExistError
+	if errors.As(err, &notExistErr) {
+		if _, _, decodeErr := bech32.DecodeAndConvert(nameOrAddress); decodeErr != nil {
+			return AccountInfo{}, err
+		}
+		acc, err = account.NewWatchOnlyFromBech32(nameOrAddress, nil)
+	}
+	if err != nil {
+		return AccountInfo{}, err
+	}
+
+	addr, err := c.addressCodec.BytesToString(acc.AccAddress())
+	if err != nil {
+		return AccountInfo{}, err
+	}
+
+	info := AccountInfo{
+		Name:    acc.Name,
+		Address: addr,
+	}
+	pubKey := acc.PubKey()
+
+	chainAcc, err := c.accountRetriever.GetAccount(c.context, acc.AccAddress())
+	switch {
+	case status.Code(errors.Cause(err)) == codes.NotFound:
+		// the account has never received funds.
+	case err != nil:
+		return AccountInfo{}, errors.Wrapf(err, "fetching account %s", addr)
+	default:
+		info.AccountNumber = chainAcc.GetAccountNumber()
+		info.Sequence = chainAcc.GetSequence()
+		if pubKey == nil {
+			pubKey = chainAcc.GetPubKey()
+		}
+		if vacc, ok := chainAcc.(vestingexported.VestingAccount); ok {
+			info.Vesting = vestingSchedule(vacc, time.Now())
+		}
+	}
+
+	if pubKey != nil {
+		info.PubKeyType = pubKey.Type()
+	}
+
+	if info.Balances, err = c.allBalances(ctx, addr); err != nil {
+		return AccountInfo{}, err
+	}
+
+	if info.Delegated, info.Unbonding, err = c.staked(ctx, addr); err != nil {
+		return AccountInfo{}, err
+	}
+
+	if info.Escrowed, err = c.escrowed(ctx, addr); err != nil {
+		return AccountInfo{}, err
+	}
+
+	return info, nil
+}
+
+func vestingSchedule(vacc vestingexported.VestingAccount, t time.Time) *VestingSchedule {
+	s := &VestingSchedule{
+		OriginalVesting:  vacc.GetOriginalVesting(),
+		EndTime:          time.Unix(vacc.GetEndTime(), 0),
+		Vested:           vacc.GetVestedCoins(t),
+		Vesting:          vacc.GetVestingCoins(t),
+		DelegatedFree:    vacc.GetDelegatedFree(),
+		DelegatedVesting: vacc.GetDelegatedVesting(),
+	}
+	if start := vacc.GetStartTime(); start != 0 {
+		s.StartTime = time.Unix(start, 0)
+	}
+
+	switch vacc := vacc.(type) {
+	case *vestingtypes.ContinuousVestingAccount:
+		s.Type = "continuous"
+	case *vestingtypes.DelayedVestingAccount:
+		s.Type = "delayed"
+	case *vestingtypes.PeriodicVestingAccount:
+		s.Type = "periodic"
+		for _, p := range vacc.VestingPeriods {
+			s.Periods = append(s.Periods, VestingPeriod{
+				Length: time.Duration(p.Length) * time.Second,
+				Amount: p.Amount,
+			})
+		}
+	case *vestingtypes.PermanentLockedAccount:
+		s.Type = "permanent-locked"
+	}
+
+	return s
+}
+
+func (c Client) allBalances(ctx context.Context, addr string) (sdktypes.Coins, error) {
+	balances := sdktypes.NewCoins()
+	for pageReq := (&query.PageRequest{}); pageReq != nil; {
+		resp, err := c.bankQueryClient.AllBalances(ctx, &banktypes.QueryAllBalancesRequest{
+			Address:    addr,
+			Pagination: pageReq,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching balances of %s", addr)
+		}
+		balances = balances.Add(resp.Balances...)
+		pageReq = nextPage(resp.Pagination)
+	}
+
+	return balances, nil
+}
+
+// staked returns the delegated and unbonding coins of addr in bond denom.
+func (c Client) staked(ctx context.Context, addr string) (delegated, unbonding sdktypes.Coin, err error) {
+	queryClient := staking.NewQueryClient(c.context)
+
+	params, err := queryClient.Params(ctx, &staking.QueryParamsRequest{})
+	if err != nil {
+		return delegated, unbonding, errors.Wrap(err, "fetching staking params")
+	}
+	bondDenom := params.Params.BondDenom
+
+	delegated = sdktypes.NewInt64Coin(bondDenom, 0)
+	for pageReq := (&query.PageRequest{}); pageReq != nil; {
+		resp, err := queryClient.DelegatorDelegations(ctx, &staking.QueryDelegatorDelegationsRequest{
+			DelegatorAddr: addr,
+			Pagination:    pageReq,
+		})
+		if err != nil {
+			return delegated, unbonding, errors.Wrapf(err, "fetching delegations of %s", addr)
+		}
+		for _, d := range resp.DelegationResponses {
+			delegated = delegated.Add(d.Balance)
+		}
+		pageReq = nextPage(resp.Pagination)
+	}
+
+	unbonding = sdktypes.NewInt64Coin(bondDenom, 0)
+	for pageReq := (&query.PageRequest{}); pageReq != nil; {
+		resp, err := queryClient.DelegatorUnbondingDelegations(ctx, &staking.QueryDelegatorUnbondingDelegationsRequest{
+			DelegatorAddr: addr,
+			Pagination:    pageReq,
+		})
+		if err != nil {
+			return delegated, unbonding, errors.Wrapf(err, "fetching unbonding delegations of %s", addr)
+		}
+		for _, u := range resp.UnbondingResponses {
+			for _, entry := range u.Entries {
+				unbonding = unbonding.AddAmount(entry.Balance)
+			}
+		}
+		pageReq = nextPage(resp.Pagination)
+	}
+
+	return delegated, unbonding, nil
+}
+
+// escrowed returns the total escrowed in the open deployments of owner.
+func (c Client) escrowed(ctx context.Context, owner string) (sdktypes.DecCoins, error) {
+	queryClient := v1beta2.NewQueryClient(c.context)
+
+	total := sdktypes.NewDecCoins()
+	for pageReq := (&query.PageRequest{}); pageReq != nil; {
+		resp, err := queryClient.Deployments(ctx, &v1beta2.QueryDeploymentsRequest{
+			Filters: v1beta2.DeploymentFilters{
+				Owner: owner,
+				State: v1beta2.DeploymentActive.String(),
+			},
+			Pagination: pageReq,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching deployments of %s", owner)
+		}
+		for _, d := range resp.Deployments {
+			for _, coin := range []sdktypes.DecCoin{d.EscrowAccount.Balance, d.EscrowAccount.Funds} {
+				if coin.Denom != "" && !coin.Amount.IsNil() && coin.IsPositive() {
+					total = total.Add(coin)
+				}
+			}
+		}
+		pageReq = nextPage(resp.Pagination)
+	}
+
+	return total, nil
+}
+
+// nextPage returns the request of the page following resp, nil on the last page.
+func nextPage(resp *query.PageResponse) *query.PageRequest {
+	if resp == nil || len(resp.NextKey) == 0 {
+		return nil
+	}
+	return &query.PageRequest{Key: resp.NextKey}
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"akashrpcclient/address"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// accountRetriever implements client.AccountRetriever like
+// authtypes.AccountRetriever, but encodes addresses with the address codec of
+// the client instead of the SDK global config.
+type accountRetriever struct {
+	addressCodec address.Codec
+}
+
+func (ar accountRetriever) GetAccount(clientCtx client.Context, addr sdktypes.AccAddress) (client.Account, error) {
+	account, _, err := ar.GetAccountWithHeight(clientCtx, addr)
+	return account, err
+}
+
+func (ar accountRetriever) GetAccountWithHeight(clientCtx client.Context, addr sdktypes.AccAddress) (client.Account, int64, error) {
+	bech32Addr, err := ar.addressCodec.BytesToString(addr)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var header metadata.MD
+
+	queryClient := authtypes.NewQueryClient(clientCtx)
+	res, err := queryClient.Account(context.Background(), &authtypes.QueryAccountRequest{Address: bech32Addr}, grpc.Header(&header))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	blockHeight := header.Get(grpctypes.GRPCBlockHeightHeader)
+	if l := len(blockHeight); l != 1 {
+		return nil, 0, fmt.Errorf("unexpected '%s' header length; got %d, expected: %d", grpctypes.GRPCBlockHeightHeader, l, 1)
+	}
+
+	nBlockHeight, err := strconv.Atoi(blockHeight[0])
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to parse block height: %w", err)
+	}
+
+	var acc authtypes.AccountI
+	if err := clientCtx.InterfaceRegistry.UnpackAny(res.Account, &acc); err != nil {
+		return nil, 0, err
+	}
+
+	return acc, int64(nBlockHeight), nil
+}
+
+func (ar accountRetriever) EnsureExists(clientCtx client.Context, addr sdktypes.AccAddress) error {
+	_, err := ar.GetAccount(clientCtx, addr)
+	return err
+}
+
+func (ar accountRetriever) GetAccountNumberSequence(clientCtx client.Context, addr sdktypes.AccAddress) (uint64, uint64, error) {
+	acc, err := ar.GetAccount(clientCtx, addr)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return acc.GetAccountNumber(), acc.GetSequence(), nil
+}
@@ -0,0 +1,314 @@
+package client
+
+import (
+	"context"
+	"reflect"
+
+	"akashrpcclient/account"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultBatchMaxGas   = 3000000
+	defaultBatchMaxBytes = 100000
+
+	// signatureSize accounts for the signature and signer info which are not
+	// part of the unsigned tx measured while packing a batch.
+	signatureSize = 256
+)
+
+// ErrMsgExceedsBatchLimits is returned for a message which does not fit in a
+// tx on its own under the batch gas and bytes ceiling.
+var ErrMsgExceedsBatchLimits = errors.New("message exceeds batch gas or bytes limit")
+
+// ErrBatchMsgsChanged is returned for the messages of a batch tx whose
+// BeforeBuild hooks changed its messages.
+var ErrBatchMsgsChanged = errors.New("BeforeBuild hooks changed the messages of a batch tx")
+
+// BatchOption configures BroadcastBatch.
+type BatchOption func(*batchOptions)
+
+type batchOptions struct {
+	maxGas   uint64
+	maxBytes int
+}
+
+// WithBatchMaxGas sets the maximum gas of each tx of the batch.
+func WithBatchMaxGas(gas uint64) BatchOption {
+	return func(o *batchOptions) {
+		o.maxGas = gas
+	}
+}
+
+// WithBatchMaxBytes sets the maximum size in bytes of each tx of the batch.
+func WithBatchMaxBytes(size int) BatchOption {
+	return func(o *batchOptions) {
+		o.maxBytes = size
+	}
+}
+
+// BatchResult is the outcome of a single message of a batch.
+type BatchResult struct {
+	// Msg is the message broadcasted.
+	Msg sdktypes.Msg
+
+	// TxIndex is the index of the tx which included the message, -1 if the
+	// message was not broadcasted.
+	TxIndex int
+
+	// Response is the response of the tx which included the message.
+	Response Response
+
+	// Err is the error which prevented the message from being committed.
+	Err error
+}
+
+type batch struct {
+	msgs    []sdktypes.Msg
+	indexes []int
+
+	// gas is the sum of the gas of the msgs simulated one by one, an upper
+	// bound of the gas of the batch as each simulation includes the cost of
+	// the tx itself.
+	gas uint64
+}
+
+// BroadcastBatch packs msgs into as few txs as possible under the configured
+// gas and bytes ceiling, broadcasts them with consecutive sequences and waits
+// for their inclusion. It returns one result per message, in the order of msgs.
+// The error is only set when the batch could not be prepared at all.
+//
+// The gas of the messages and of each tx is always simulated, whatever the
+// gas setting of the client. BeforeBuild hooks run once per tx and may set
+// its memo, but must not change its messages, which are already packed.
+func (c Client) BroadcastBatch(goCtx context.Context, account account.Account, msgs []sdktypes.Msg, options ...BatchOption) ([]BatchResult, error) {
+	if account.IsWatchOnly() {
+		return nil, ErrWatchOnlyAccount
+	}
+
+	o := batchOptions{
+		maxGas:   defaultBatchMaxGas,
+		maxBytes: defaultBatchMaxBytes,
+	}
+	for _, apply := range options {
+		apply(&o)
+	}
+
+	results := make([]BatchResult, len(msgs))
+	for i, msg := range msgs {
+		results[i] = BatchResult{Msg: msg, TxIndex: -1}
+	}
+
+	ctx, txf, batches, err := c.planBatches(goCtx, account, msgs, o, results)
+	if err != nil {
+		return nil, err
+	}
+
+	type sentTx struct {
+		service TxService
+		batch   batch
+		hash    string
+	}
+
+	var (
+		sent []sentTx
+		seq  = txf.Sequence()
+	)
+	for _, b := range batches {
+		fail := func(err error) {
+			for _, i := range b.indexes {
+				results[i].Err = err
+			}
+		}
+
+		info := &TxInfo{
+			AccountName: account.Name,
+			From:        ctx.GetFromAddress(),
+			Msgs:        append([]sdktypes.Msg{}, b.msgs...),
+		}
+		if err := c.interceptors.beforeBuild(goCtx, info); err != nil {
+			fail(err)
+			continue
+		}
+		if !sameMsgs(info.Msgs, b.msgs) {
+			fail(ErrBatchMsgsChanged)
+			continue
+		}
+
+		service, err := c.buildBatchTx(ctx, txf.WithMemo(info.Memo), seq, info, o)
+		if err != nil {
+			fail(err)
+			continue
+		}
+
+		// the sequence is consumed by every tx which may have reached the
+		// mempool. A tx whose broadcast outcome is unknown, e.g. after a
+		// timeout, is waited for like the accepted ones.
+		if _, err := service.broadcast(goCtx); NotAccepted(err) {
+			fail(err)
+			continue
+		}
+		seq++
+
+		for _, i := range b.indexes {
+			results[i].TxIndex = len(sent)
+		}
+		sent = append(sent, sentTx{service, b, service.info.TxHash})
+	}
+
+	for _, s := range sent {
+		resp, err := s.service.waitForInclusion(goCtx, s.hash)
+		for _, i := range s.batch.indexes {
+			results[i].Response = resp
+			results[i].Err = err
+		}
+	}
+
+	return results, nil
+}
+
+// buildBatchTx simulates the tx of a batch to set its gas and builds it with
+// sequence seq, checking it is still under the ceiling.
+func (c Client) buildBatchTx(ctx client.Context, txf tx.Factory, seq uint64, info *TxInfo, o batchOptions) (TxService, error) {
+	// the tx is simulated with the sequence of the account on chain, the
+	// previous txs of the batch are not committed yet.
+	gas, err := c.simulateGas(ctx, txf, info.Msgs...)
+	if err != nil {
+		return TxService{}, err
+	}
+	if gas > o.maxGas {
+		return TxService{}, ErrMsgExceedsBatchLimits
+	}
+
+	service, err := c.buildTx(ctx, txf.WithGas(gas).WithSequence(seq), info)
+	if err != nil {
+		return TxService{}, err
+	}
+
+	bz, err := c.context.TxConfig.TxEncoder()(service.txBuilder.GetTx())
+	if err != nil {
+		return TxService{}, errors.WithStack(err)
+	}
+	if len(bz)+signatureSize > o.maxBytes {
+		return TxService{}, ErrMsgExceedsBatchLimits
+	}
+
+	return service, nil
+}
+
+// planBatches simulates msgs one by one to pack them in batches. Messages
+// which fit in no batch get their error set in results.
+func (c Client) planBatches(goCtx context.Context, account account.Account, msgs []sdktypes.Msg, o batchOptions, results []BatchResult) (client.Context, tx.Factory, []batch, error) {
+	if !c.generateOnly {
+		addr, err := account.Address(c.addressCodec.AccountPrefix())
+		if err != nil {
+			return client.Context{}, tx.Factory{}, nil, errors.WithStack(err)
+		}
+		if err := c.makeSureAccountHasTokens(goCtx, addr); err != nil {
+			return client.Context{}, tx.Factory{}, nil, err
+		}
+	}
+
+	ctx := c.context.
+		WithFromName(account.Name).
+		WithFromAddress(account.AccAddress())
+
+	txf, err := c.prepareFactory(ctx)
+	if err != nil {
+		return client.Context{}, tx.Factory{}, nil, err
+	}
+
+	fitsSize := func(msgs []sdktypes.Msg) (bool, error) {
+		size, err := c.unsignedTxSize(txf.WithGas(o.maxGas), msgs...)
+		if err != nil {
+			return false, err
+		}
+		return size+signatureSize <= o.maxBytes, nil
+	}
+
+	var (
+		batches []batch
+		cur     batch
+	)
+	for i, msg := range msgs {
+		gas, err := c.simulateGas(ctx, txf, msg)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		if gas > o.maxGas {
+			results[i].Err = ErrMsgExceedsBatchLimits
+			continue
+		}
+
+		if len(cur.msgs) != 0 && cur.gas+gas <= o.maxGas {
+			candidate := append(append([]sdktypes.Msg{}, cur.msgs...), msg)
+			fits, err := fitsSize(candidate)
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			if fits {
+				cur = batch{
+					msgs:    candidate,
+					indexes: append(cur.indexes, i),
+					gas:     cur.gas + gas,
+				}
+				continue
+			}
+		}
+
+		fits, err := fitsSize([]sdktypes.Msg{msg})
+		if err == nil && !fits {
+			err = ErrMsgExceedsBatchLimits
+		}
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		if len(cur.msgs) != 0 {
+			batches = append(batches, cur)
+		}
+		cur = batch{
+			msgs:    []sdktypes.Msg{msg},
+			indexes: []int{i},
+			gas:     gas,
+		}
+	}
+	if len(cur.msgs) != 0 {
+		batches = append(batches, cur)
+	}
+
+	return ctx, txf, batches, nil
+}
+
+// sameMsgs reports whether a and b hold the same messages in the same order.
+func sameMsgs(a, b []sdktypes.Msg) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !reflect.DeepEqual(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// unsignedTxSize returns the size in bytes of the encoded unsigned tx of msgs.
+func (c Client) unsignedTxSize(txf tx.Factory, msgs ...sdktypes.Msg) (int, error) {
+	txb, err := txf.BuildUnsignedTx(msgs...)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	bz, err := c.context.TxConfig.TxEncoder()(txb.GetTx())
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return len(bz), nil
+}
@@ -0,0 +1,232 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"akashrpcclient/account"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	gogogrpc "github.com/gogo/protobuf/grpc"
+)
+
+// amountGasometer simulates the gas of msgs as the sum of the amounts of
+// their MsgSend.
+type amountGasometer struct{}
+
+func (amountGasometer) CalculateGas(_ gogogrpc.ClientConn, _ tx.Factory, msgs ...sdktypes.Msg) (*txtypes.SimulateResponse, uint64, error) {
+	var gas uint64
+	for _, msg := range msgs {
+		gas += msg.(*banktypes.MsgSend).Amount.AmountOf("uakt").Uint64()
+	}
+	return &txtypes.SimulateResponse{}, gas, nil
+}
+
+// existingAccountRetriever reports every account as existing.
+type existingAccountRetriever struct {
+	client.AccountRetriever
+}
+
+func (existingAccountRetriever) EnsureExists(client.Context, sdktypes.AccAddress) error {
+	return nil
+}
+
+// msgsInterceptor replaces the messages of every tx in BeforeBuild.
+type msgsInterceptor struct {
+	NopInterceptor
+
+	msgs []sdktypes.Msg
+}
+
+func (i msgsInterceptor) BeforeBuild(_ context.Context, info *TxInfo) error {
+	info.Msgs = i.msgs
+	return nil
+}
+
+func newBatchTestClient(t *testing.T) (Client, account.Account) {
+	t.Helper()
+
+	interfaceRegistry := codectypes.NewInterfaceRegistry()
+	banktypes.RegisterInterfaces(interfaceRegistry)
+	txConfig := authtx.NewTxConfig(codec.NewProtoCodec(interfaceRegistry), authtx.DefaultSignModes)
+
+	kr := keyring.NewInMemory()
+	info, _, err := kr.NewMnemonic("batch", keyring.English, sdktypes.FullFundraiserPath, "", hd.Secp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := Client{
+		TxFactory: tx.Factory{}.
+			WithTxConfig(txConfig).
+			WithChainID("test").
+			WithAccountNumber(1).
+			WithSequence(1),
+		context:          client.Context{}.WithTxConfig(txConfig).WithInterfaceRegistry(interfaceRegistry),
+		accountRetriever: existingAccountRetriever{},
+		gasometer:        amountGasometer{},
+		generateOnly:     true,
+	}
+	return c, account.Account{Name: "batch", Info: info}
+}
+
+// sendGas returns a MsgSend whose simulated gas, margin included, is gas.
+func sendGas(from sdktypes.AccAddress, gas int64) sdktypes.Msg {
+	return banktypes.NewMsgSend(from, from, sdktypes.NewCoins(sdktypes.NewInt64Coin("uakt", gas-20000)))
+}
+
+func TestPlanBatches(t *testing.T) {
+	c, acc := newBatchTestClient(t)
+	from := acc.AccAddress()
+
+	size, err := c.unsignedTxSize(c.TxFactory.WithGas(defaultBatchMaxGas), sendGas(from, 50000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// txSize is the size of the tx of a single message, signature included.
+	txSize := size + signatureSize
+
+	tests := []struct {
+		name     string
+		gas      []int64
+		maxGas   uint64
+		maxBytes int
+
+		want []batch
+
+		// exceeding lists the messages which fit in no tx.
+		exceeding []int
+	}{
+		{
+			name:     "single batch",
+			gas:      []int64{50000, 50000, 50000},
+			maxGas:   defaultBatchMaxGas,
+			maxBytes: defaultBatchMaxBytes,
+			want:     []batch{{indexes: []int{0, 1, 2}, gas: 150000}},
+		},
+		{
+			name:     "split by gas",
+			gas:      []int64{50000, 50000, 50000},
+			maxGas:   100000,
+			maxBytes: defaultBatchMaxBytes,
+			want: []batch{
+				{indexes: []int{0, 1}, gas: 100000},
+				{indexes: []int{2}, gas: 50000},
+			},
+		},
+		{
+			name:     "split by bytes",
+			gas:      []int64{50000, 50000, 50000},
+			maxGas:   defaultBatchMaxGas,
+			maxBytes: txSize + 10,
+			want: []batch{
+				{indexes: []int{0}, gas: 50000},
+				{indexes: []int{1}, gas: 50000},
+				{indexes: []int{2}, gas: 50000},
+			},
+		},
+		{
+			name:      "message above the gas limit",
+			gas:       []int64{50000, 150000, 50000},
+			maxGas:    100000,
+			maxBytes:  defaultBatchMaxBytes,
+			want:      []batch{{indexes: []int{0, 2}, gas: 100000}},
+			exceeding: []int{1},
+		},
+		{
+			name:      "messages above the bytes limit",
+			gas:       []int64{50000, 50000},
+			maxGas:    defaultBatchMaxGas,
+			maxBytes:  txSize - 10,
+			exceeding: []int{0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs := make([]sdktypes.Msg, len(tt.gas))
+			results := make([]BatchResult, len(tt.gas))
+			for i, gas := range tt.gas {
+				msgs[i] = sendGas(from, gas)
+			}
+
+			o := batchOptions{maxGas: tt.maxGas, maxBytes: tt.maxBytes}
+			_, _, batches, err := c.planBatches(context.Background(), acc, msgs, o, results)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := range tt.want {
+				for _, j := range tt.want[i].indexes {
+					tt.want[i].msgs = append(tt.want[i].msgs, msgs[j])
+				}
+			}
+			if len(batches) != len(tt.want) || (len(batches) != 0 && !reflect.DeepEqual(batches, tt.want)) {
+				t.Errorf("planBatches() = %+v, want %+v", batches, tt.want)
+			}
+
+			for i, r := range results {
+				exceeding := false
+				for _, j := range tt.exceeding {
+					exceeding = exceeding || i == j
+				}
+				if exceeding != errors.Is(r.Err, ErrMsgExceedsBatchLimits) {
+					t.Errorf("message %d error = %v, exceeding the limits: %t", i, r.Err, exceeding)
+				}
+			}
+		})
+	}
+}
+
+func TestBroadcastBatchMsgsChanged(t *testing.T) {
+	c, acc := newBatchTestClient(t)
+	from := acc.AccAddress()
+	msgs := []sdktypes.Msg{sendGas(from, 50000), sendGas(from, 50000)}
+
+	c.interceptors = interceptorChain{msgsInterceptor{msgs: msgs[:1]}}
+	results, err := c.BroadcastBatch(context.Background(), acc, msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range results {
+		if !errors.Is(r.Err, ErrBatchMsgsChanged) || r.TxIndex != -1 {
+			t.Errorf("result %d = %+v, want an unbroadcasted ErrBatchMsgsChanged", i, r)
+		}
+	}
+}
+
+func TestSameMsgs(t *testing.T) {
+	from := sdktypes.AccAddress("from________________")
+	a, b := sendGas(from, 50000), sendGas(from, 60000)
+
+	tests := []struct {
+		name string
+		x, y []sdktypes.Msg
+		want bool
+	}{
+		{"equal", []sdktypes.Msg{a, b}, []sdktypes.Msg{a, b}, true},
+		{"equal copies", []sdktypes.Msg{a}, []sdktypes.Msg{sendGas(from, 50000)}, true},
+		{"reordered", []sdktypes.Msg{a, b}, []sdktypes.Msg{b, a}, false},
+		{"removed", []sdktypes.Msg{a, b}, []sdktypes.Msg{a}, false},
+		{"changed", []sdktypes.Msg{a}, []sdktypes.Msg{b}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameMsgs(tt.x, tt.y); got != tt.want {
+				t.Errorf("sameMsgs() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// Status returns the status of the node.
+func (c Client) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
+	resp, err := c.RPC.Status(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching node status")
+	}
+	return resp, nil
+}
+
+// LatestBlockHeight returns the height of the latest block of the node.
+func (c Client) LatestBlockHeight(ctx context.Context) (int64, error) {
+	resp, err := c.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return resp.SyncInfo.LatestBlockHeight, nil
+}
+
+// heightParam returns the RPC height parameter of height, nil for the latest
+// height when height is 0.
+func heightParam(height int64) *int64 {
+	if height == 0 {
+		return nil
+	}
+	return &height
+}
+
+// Block returns the block at height, the latest block if height is 0.
+func (c Client) Block(ctx context.Context, height int64) (*ctypes.ResultBlock, error) {
+	if height < 0 {
+		return nil, errors.Errorf("invalid block height %d", height)
+	}
+
+	resp, err := c.RPC.Block(ctx, heightParam(height))
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching block at height %d", height)
+	}
+	if resp.Block == nil {
+		return nil, errors.Errorf("block at height %d not found", height)
+	}
+
+	return resp, nil
+}
+
+// Header returns the header of the block at height, the latest block if
+// height is 0. It is fetched from the block commit, without the block txs.
+func (c Client) Header(ctx context.Context, height int64) (tmtypes.Header, error) {
+	if height < 0 {
+		return tmtypes.Header{}, errors.Errorf("invalid block height %d", height)
+	}
+
+	resp, err := c.RPC.Commit(ctx, heightParam(height))
+	if err != nil {
+		return tmtypes.Header{}, errors.Wrapf(err, "fetching header at height %d", height)
+	}
+	if resp.SignedHeader.Header == nil {
+		return tmtypes.Header{}, errors.Errorf("header at height %d not found", height)
+	}
+
+	return *resp.SignedHeader.Header, nil
+}
+
+// BlockTime returns the time of the block at height, the latest block if
+// height is 0.
+func (c Client) BlockTime(ctx context.Context, height int64) (time.Time, error) {
+	header, err := c.Header(ctx, height)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return header.Time, nil
+}
+
+// AverageBlockTime returns the average time between the last blocks of
+// window, measured from the time of the latest block and the block window
+// blocks before it.
+func (c Client) AverageBlockTime(ctx context.Context, window int64) (time.Duration, error) {
+	if window <= 0 {
+		return 0, errors.Errorf("invalid block window %d", window)
+	}
+
+	latest, err := c.Header(ctx, 0)
+	if err != nil {
+		return 0, err
+	}
+	if latest.Height <= window {
+		window = latest.Height - 1
+	}
+	if window <= 0 {
+		return 0, errors.Errorf("not enough blocks to measure block time at height %d", latest.Height)
+	}
+
+	past, err := c.Header(ctx, latest.Height-window)
+	if err != nil {
+		return 0, err
+	}
+
+	return latest.Time.Sub(past.Time) / time.Duration(window), nil
+}
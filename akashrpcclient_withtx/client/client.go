@@ -5,15 +5,19 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
+	"log"
+	"net"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"akashrpcclient/account"
+	"akashrpcclient/address"
 
+	dtypes "github.com/akash-network/node/x/deployment/types/v1beta2"
+	mtypes "github.com/akash-network/node/x/market/types/v1beta2"
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/flags"
 	"github.com/cosmos/cosmos-sdk/client/tx"
@@ -21,10 +25,14 @@ import (
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
 	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
 	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	staking "github.com/cosmos/cosmos-sdk/x/staking/types"
 	gogogrpc "github.com/gogo/protobuf/grpc"
@@ -79,8 +87,11 @@ type Client struct {
 	bankQueryClient banktypes.QueryClient
 	gasometer       Gasometer
 	signer          Signer
+	interceptors    interceptorChain
+	logger          *log.Logger
 
 	addressPrefix string
+	addressCodec  address.Codec
 
 	nodeAddress string
 	out         io.Writer
@@ -95,6 +106,7 @@ type Client struct {
 	keyringServiceName string
 	keyringBackend     account.KeyringBackend
 	keyringDir         string
+	keyringPassphrase  account.PassphraseProvider
 
 	gas           string
 	gasPrices     string
@@ -111,6 +123,7 @@ func New(ctx context.Context, options ...Option) (Client, error) {
 		addressPrefix:  "akash",
 		out:            io.Discard,
 		gas:            "auto",
+		logger:         log.New(os.Stderr, "", log.LstdFlags),
 	}
 
 	var err error
@@ -153,18 +166,24 @@ func New(ctx context.Context, options ...Option) (Client, error) {
 		account.WithKeyringServiceName(c.keyringServiceName),
 		account.WithKeyringBackend(c.keyringBackend),
 		account.WithHome(c.keyringDir),
+		account.WithPassphraseProvider(c.keyringPassphrase),
 	)
 
 	if err != nil {
 		return Client{}, err
 	}
 
-	c.context = c.newContext()
-	c.TxFactory = newFactory(c.context)
+	if c.addressCodec, err = address.NewCodec(c.addressPrefix); err != nil {
+		return Client{}, err
+	}
 
 	if c.accountRetriever == nil {
-		c.accountRetriever = authtypes.AccountRetriever{}
+		c.accountRetriever = accountRetriever{c.addressCodec}
 	}
+
+	c.context = c.newContext()
+	c.TxFactory = newFactory(c.context)
+
 	if c.bankQueryClient == nil {
 		c.bankQueryClient = banktypes.NewQueryClient(c.context)
 	}
@@ -172,11 +191,8 @@ func New(ctx context.Context, options ...Option) (Client, error) {
 		c.gasometer = gasometer{}
 	}
 	if c.signer == nil {
-		c.signer = signer{}
+		c.signer = signer{c.context.TxConfig}
 	}
-	// set address prefix in SDK global config
-	c.SetConfigAddressPrefix()
-
 	return c, nil
 }
 
@@ -186,6 +202,45 @@ func WithAddressPrefix(prefix string) Option {
 	}
 }
 
+// WithLogger sets the logger of the errors which do not fail a tx, like the
+// ones of the AfterBroadcast and AfterInclusion hooks. They are logged to
+// stderr by default.
+func WithLogger(logger *log.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithGenerateOnly makes the client build txs without requiring a funded
+// account, e.g. to hand them over to an offline signer.
+func WithGenerateOnly(generateOnly bool) Option {
+	return func(c *Client) {
+		c.generateOnly = generateOnly
+	}
+}
+
+// WithKeyringBackend sets the backend of the keyring.
+func WithKeyringBackend(backend account.KeyringBackend) Option {
+	return func(c *Client) {
+		c.keyringBackend = backend
+	}
+}
+
+// WithKeyringDir sets the directory of the keyring, the home directory by default.
+func WithKeyringDir(dir string) Option {
+	return func(c *Client) {
+		c.keyringDir = dir
+	}
+}
+
+// WithKeyringPassphraseProvider sets the provider of the passphrase unlocking
+// encrypted keyring backends.
+func WithKeyringPassphraseProvider(provider account.PassphraseProvider) Option {
+	return func(c *Client) {
+		c.keyringPassphrase = provider
+	}
+}
+
 func (c Client) newContext() client.Context {
 	var (
 		amino             = codec.NewLegacyAmino()
@@ -200,6 +255,10 @@ func (c Client) newContext() client.Context {
 	staking.RegisterInterfaces(interfaceRegistry)
 	cryptocodec.RegisterInterfaces(interfaceRegistry)
 	banktypes.RegisterInterfaces(interfaceRegistry)
+	vestingtypes.RegisterInterfaces(interfaceRegistry)
+	authz.RegisterInterfaces(interfaceRegistry)
+	dtypes.RegisterInterfaces(interfaceRegistry)
+	mtypes.RegisterInterfaces(interfaceRegistry)
 
 	return client.Context{}.
 		WithChainID(c.chainID).
@@ -230,46 +289,47 @@ func newFactory(clientCtx client.Context) tx.Factory {
 		WithTxConfig(clientCtx.TxConfig)
 }
 
-// protects sdktypes.Config.
-var mconf sync.Mutex
-
 // SetConfigAddressPrefix sets the account prefix in the SDK global config.
+// The client does not depend on the global config, which is only needed by
+// SDK helpers such as sdktypes.AccAddress.String. Processes using a single
+// chain may call it once at start-up; it is not safe for concurrent use.
 func (c Client) SetConfigAddressPrefix() {
-	mconf.Lock()
-	defer mconf.Unlock()
 	config := sdktypes.GetConfig()
-	config.SetBech32PrefixForAccount(c.addressPrefix, c.addressPrefix+"pub")
+	config.SetBech32PrefixForAccount(c.addressCodec.AccountPrefix(), c.addressCodec.AccountPubPrefix())
+}
+
+// AddressCodec returns the address codec of the chain of the client.
+func (c Client) AddressCodec() address.Codec {
+	return c.addressCodec
 }
 
 func (c Client) Context() client.Context {
 	return c.context
 }
 
+// Account returns an account of the keyring by its name or its address.
 func (c Client) Account(nameOrAddress string) (account.Account, error) {
-	defer c.lockBech32Prefix()()
-
 	acc, err := c.AccountRegistry.GetByName(nameOrAddress)
-	if err == nil {
-		return acc, nil
+	var notExistErr *account.AccountDoesNotExistError
+	if !errors.As(err, &notExistErr) {
+		return acc, err
 	}
-	x, err := c.AccountRegistry.GetByAddress(nameOrAddress)
-	fmt.Println("GetByAddress result: ", x)
-	fmt.Println("GetByAddress error: ", err)
 
-	return x, err
-}
+	// not a known name, look it up as an address if it is one.
+	if _, _, decodeErr := bech32.DecodeAndConvert(nameOrAddress); decodeErr != nil {
+		return account.Account{}, err
+	}
 
-func (c Client) lockBech32Prefix() (unlockFn func()) {
-	mconf.Lock()
-	config := sdktypes.GetConfig()
-	config.SetBech32PrefixForAccount(c.addressPrefix, c.addressPrefix+"pub")
-	return mconf.Unlock
+	return c.AccountRegistry.GetByAddress(nameOrAddress)
 }
 
+// BroadcastTx creates, signs and broadcasts a tx of msgs, and waits for its
+// inclusion. It fails with a TxNotAcceptedError when the tx is known not to be
+// committed, see NotAccepted.
 func (c Client) BroadcastTx(ctx context.Context, account account.Account, msgs ...sdktypes.Msg) (Response, error) {
 	txService, err := c.CreateTx(ctx, account, msgs...)
 	if err != nil {
-		return Response{}, err
+		return Response{}, notAccepted(err)
 	}
 
 	return txService.Broadcast(ctx)
@@ -283,10 +343,8 @@ type Response struct {
 }
 
 func (c Client) CreateTx(goCtx context.Context, account account.Account, msgs ...sdktypes.Msg) (TxService, error) {
-	defer c.lockBech32Prefix()()
-
 	if !c.generateOnly {
-		addr, err := account.Address(c.addressPrefix)
+		addr, err := account.Address(c.addressCodec.AccountPrefix())
 		if err != nil {
 			return TxService{}, errors.WithStack(err)
 		}
@@ -295,34 +353,72 @@ func (c Client) CreateTx(goCtx context.Context, account account.Account, msgs ..
 		}
 	}
 
-	sdkaddr := account.Info.GetAddress()
+	sdkaddr := account.AccAddress()
 
 	ctx := c.context.
 		WithFromName(account.Name).
 		WithFromAddress(sdkaddr)
 
+	info := &TxInfo{
+		AccountName: account.Name,
+		From:        sdkaddr,
+		Msgs:        msgs,
+	}
+	if err := c.interceptors.beforeBuild(goCtx, info); err != nil {
+		return TxService{}, err
+	}
+	msgs = info.Msgs
+
 	txf, err := c.prepareFactory(ctx)
 	if err != nil {
 		return TxService{}, err
 	}
+	txf = txf.WithMemo(info.Memo)
 
-	var gas uint64
-	if c.gas != "" && c.gas != GasAuto {
-		gas, err = strconv.ParseUint(c.gas, 10, 64)
-		if err != nil {
-			return TxService{}, errors.WithStack(err)
-		}
-	} else {
-		_, gas, err = c.gasometer.CalculateGas(ctx, txf, msgs...)
-		if err != nil {
-			return TxService{}, errors.WithStack(err)
+	gas, err := c.estimateGas(ctx, txf, msgs...)
+	if err != nil {
+		return TxService{}, err
+	}
+
+	txService, err := c.buildTx(ctx, txf.WithGas(gas), info)
+	if err != nil {
+		return TxService{}, err
+	}
+
+	if account.IsWatchOnly() {
+		txService.watchOnly = true
+		if err := txService.setSignerInfo(account.PubKey()); err != nil {
+			return TxService{}, err
 		}
-		// the simulated gas can vary from the actual gas needed for a real transaction
-		// we add an amount to ensure sufficient gas is provided
-		gas += 20000
 	}
 
-	txf = txf.WithGas(gas)
+	return txService, nil
+}
+
+// estimateGas returns the gas configured for the client, or simulates msgs
+// when gas is set to auto.
+func (c Client) estimateGas(ctx client.Context, txf tx.Factory, msgs ...sdktypes.Msg) (uint64, error) {
+	if c.gas != "" && c.gas != GasAuto {
+		gas, err := strconv.ParseUint(c.gas, 10, 64)
+		return gas, errors.WithStack(err)
+	}
+
+	return c.simulateGas(ctx, txf, msgs...)
+}
+
+// simulateGas simulates msgs and returns the gas they need.
+func (c Client) simulateGas(ctx client.Context, txf tx.Factory, msgs ...sdktypes.Msg) (uint64, error) {
+	_, gas, err := c.gasometer.CalculateGas(ctx, txf, msgs...)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	// the simulated gas can vary from the actual gas needed for a real transaction
+	// we add an amount to ensure sufficient gas is provided
+	return gas + 20000, nil
+}
+
+// buildTx builds the unsigned tx of info with the gas and sequence of txf.
+func (c Client) buildTx(ctx client.Context, txf tx.Factory, info *TxInfo) (TxService, error) {
 	//txf = txf.WithFees(c.fees)
 
 	if c.gasPrices != "" {
@@ -333,18 +429,22 @@ func (c Client) CreateTx(goCtx context.Context, account account.Account, msgs ..
 		txf = txf.WithGasAdjustment(c.gasAdjustment)
 	}
 
-	txUnsigned, err := txf.BuildUnsignedTx(msgs...)
+	txUnsigned, err := txf.BuildUnsignedTx(info.Msgs...)
 	if err != nil {
 		return TxService{}, errors.WithStack(err)
 	}
 
 	txUnsigned.SetFeeGranter(ctx.GetFeeGranterAddress())
 
+	info.Sequence = txf.Sequence()
+	info.TxBuilder = txUnsigned
+
 	return TxService{
 		client:        c,
 		clientContext: ctx,
 		txBuilder:     txUnsigned,
 		txFactory:     txf,
+		info:          info,
 	}, nil
 }
 
@@ -384,6 +484,72 @@ func (c *Client) checkAccountBalance(ctx context.Context, address string) error
 	return fmt.Errorf("account has not enough %q balance, min. required amount: %d", c.faucetDenom, c.faucetMinAmount)
 }
 
+// TxNotAcceptedError is returned when a tx is known not to have entered the
+// mempool of the node: it failed before being broadcast, was rejected by
+// CheckTx, or never reached the node. Such a tx cannot be committed. Other
+// broadcast errors, such as timeouts, leave the outcome of the tx unknown.
+type TxNotAcceptedError struct {
+	Err error
+}
+
+func (e *TxNotAcceptedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TxNotAcceptedError) Unwrap() error {
+	return e.Err
+}
+
+func (e *TxNotAcceptedError) Cause() error {
+	return e.Err
+}
+
+// NotAccepted reports whether err is, or wraps, a TxNotAcceptedError.
+func NotAccepted(err error) bool {
+	var notAccepted *TxNotAcceptedError
+	return errors.As(err, &notAccepted)
+}
+
+// notAccepted wraps err in a TxNotAcceptedError, unless it is nil.
+func notAccepted(err error) error {
+	if err == nil || NotAccepted(err) {
+		return err
+	}
+	return &TxNotAcceptedError{err}
+}
+
+// classifyBroadcastError returns the error of a broadcast as a
+// TxNotAcceptedError when the tx is known not to be in the mempool: when
+// CheckTx rejected it, except because it is already in the mempool cache, or
+// when the node could not be reached at all.
+func classifyBroadcastError(resp *sdktypes.TxResponse, err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case resp != nil && resp.Code > 0:
+		if resp.Codespace == sdkerrors.RootCodespace && resp.Code == sdkerrors.ErrTxInMempoolCache.ABCICode() {
+			return err
+		}
+		return notAccepted(err)
+	case resp == nil && !isTimeout(err):
+		return notAccepted(err)
+	}
+	return err
+}
+
+// isTimeout reports whether err is a timeout, after which a broadcast tx may
+// have reached the node.
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return strings.Contains(err.Error(), "timed out") || strings.Contains(err.Error(), "timeout")
+}
+
 // handleBroadcastResult handles the result of broadcast messages result and checks if an error occurred.
 func handleBroadcastResult(resp *sdktypes.TxResponse, err error) error {
 	if err != nil {
@@ -492,15 +658,3 @@ func (c Client) WaitForBlockHeight(ctx context.Context, h int64) error {
 		}
 	}
 }
-
-func (c Client) LatestBlockHeight(ctx context.Context) (int64, error) {
-	resp, err := c.Status(ctx)
-	if err != nil {
-		return 0, err
-	}
-	return resp.SyncInfo.LatestBlockHeight, nil
-}
-
-func (c Client) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
-	return c.RPC.Status(ctx)
-}
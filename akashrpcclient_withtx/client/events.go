@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// eventsCapacity is the buffer size of event subscriptions.
+const eventsCapacity = 100
+
+// Subscription receives the Tendermint events of a query over a websocket
+// connection of its own, see Client.Subscribe.
+type Subscription struct {
+	// Events receives the events, it is closed when the connection ends.
+	Events <-chan ctypes.ResultEvent
+
+	rpc        *rpchttp.HTTP
+	subscriber string
+}
+
+// Subscribe opens a websocket connection to the node and subscribes
+// subscriber to the Tendermint events matching query. The subscription is
+// ended with Close, not by canceling ctx.
+func (c Client) Subscribe(ctx context.Context, subscriber, query string) (*Subscription, error) {
+	rpc, err := rpchttp.New(c.nodeAddress, "/websocket")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := rpc.Start(); err != nil {
+		return nil, errors.Wrap(err, "starting websocket connection")
+	}
+
+	out, err := rpc.Subscribe(ctx, subscriber, query, eventsCapacity)
+	if err != nil {
+		rpc.Stop()
+		return nil, errors.Wrapf(err, "subscribing to %q", query)
+	}
+	return &Subscription{
+		Events:     out,
+		rpc:        rpc,
+		subscriber: subscriber,
+	}, nil
+}
+
+// Close unsubscribes from every query and stops the websocket connection.
+func (s *Subscription) Close(ctx context.Context) error {
+	err := s.rpc.UnsubscribeAll(ctx, s.subscriber)
+	if stopErr := s.rpc.Stop(); err == nil {
+		err = stopErr
+	}
+	return errors.Wrap(err, "closing subscription")
+}
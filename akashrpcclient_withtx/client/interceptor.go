@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"log"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+// TxInfo describes a transaction while it flows through the interceptor chain.
+// Msgs and Memo may be changed by BeforeBuild hooks, the remaining fields are
+// filled in as the tx progresses through the pipeline.
+type TxInfo struct {
+	// AccountName is the keyring name of the signing account.
+	AccountName string
+
+	// From is the address of the signing account.
+	From sdktypes.AccAddress
+
+	// Msgs are the messages included in the tx.
+	Msgs []sdktypes.Msg
+
+	// Memo is the memo of the tx.
+	Memo string
+
+	// Sequence is the account sequence the tx is signed with.
+	Sequence uint64
+
+	// TxBuilder holds the unsigned tx, it is set once the tx is built.
+	TxBuilder client.TxBuilder
+
+	// TxBytes holds the encoded tx, it is set once the tx is signed.
+	TxBytes []byte
+
+	// TxHash is the hex encoded hash of TxBytes.
+	TxHash string
+}
+
+// Interceptor hooks into the tx pipeline of CreateTx and Broadcast.
+// Returning an error from BeforeBuild, BeforeSign or AfterSign aborts the tx.
+// AfterBroadcast and AfterInclusion receive the error of the pipeline, if any.
+// AfterBroadcast also runs, with a TxNotAcceptedError, when the tx is aborted
+// once BeforeSign ran, so that every interceptor sees the outcome of a tx it
+// was asked to sign.
+// Their own errors do not fail the tx, which may already be accepted by the
+// node, they are logged with the logger of the client.
+type Interceptor interface {
+	BeforeBuild(ctx context.Context, info *TxInfo) error
+	BeforeSign(ctx context.Context, info *TxInfo) error
+	AfterSign(ctx context.Context, info *TxInfo) error
+	AfterBroadcast(ctx context.Context, info *TxInfo, resp *sdktypes.TxResponse, err error) error
+	AfterInclusion(ctx context.Context, info *TxInfo, resp Response, err error) error
+}
+
+// NopInterceptor implements every Interceptor hook as a no-op. Embed it to
+// implement only the hooks you need.
+type NopInterceptor struct{}
+
+func (NopInterceptor) BeforeBuild(context.Context, *TxInfo) error { return nil }
+
+func (NopInterceptor) BeforeSign(context.Context, *TxInfo) error { return nil }
+
+func (NopInterceptor) AfterSign(context.Context, *TxInfo) error { return nil }
+
+func (NopInterceptor) AfterBroadcast(context.Context, *TxInfo, *sdktypes.TxResponse, error) error {
+	return nil
+}
+
+func (NopInterceptor) AfterInclusion(context.Context, *TxInfo, Response, error) error { return nil }
+
+// WithInterceptors appends interceptors to the tx pipeline, they run in the
+// order they are given.
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(c *Client) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// interceptorChain runs every interceptor in order.
+type interceptorChain []Interceptor
+
+func (ic interceptorChain) beforeBuild(ctx context.Context, info *TxInfo) error {
+	for _, i := range ic {
+		if err := i.BeforeBuild(ctx, info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ic interceptorChain) beforeSign(ctx context.Context, info *TxInfo) error {
+	for _, i := range ic {
+		if err := i.BeforeSign(ctx, info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ic interceptorChain) afterSign(ctx context.Context, info *TxInfo) error {
+	for _, i := range ic {
+		if err := i.AfterSign(ctx, info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// afterBroadcast runs the AfterBroadcast hooks and logs their errors.
+func (ic interceptorChain) afterBroadcast(ctx context.Context, logger *log.Logger, info *TxInfo, resp *sdktypes.TxResponse, err error) {
+	for _, i := range ic {
+		if e := i.AfterBroadcast(ctx, info, resp, err); e != nil {
+			logger.Printf("after broadcast hook of tx %s: %s", info.TxHash, e)
+		}
+	}
+}
+
+// afterInclusion runs the AfterInclusion hooks and logs their errors.
+func (ic interceptorChain) afterInclusion(ctx context.Context, logger *log.Logger, info *TxInfo, resp Response, err error) {
+	for _, i := range ic {
+		if e := i.AfterInclusion(ctx, info, resp, err); e != nil {
+			logger.Printf("after inclusion hook of tx %s: %s", info.TxHash, e)
+		}
+	}
+}
@@ -3,11 +3,80 @@ package client
 import (
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/tx"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	"github.com/pkg/errors"
 )
 
 // signer implements the Signer interface.
-type signer struct{}
+//
+// It signs as tx.Sign does, without its check that SIGN_MODE_DIRECT txs have a
+// single signer: listing the signers decodes the addresses of the messages
+// with the SDK global bech32 config, which panics when the prefix of the
+// client differs. Txs with several signers are rejected by the node instead.
+type signer struct {
+	txConfig client.TxConfig
+}
+
+func (s signer) Sign(txf tx.Factory, name string, txBuilder client.TxBuilder, overwriteSig bool) error {
+	keybase := txf.Keybase()
+	if keybase == nil {
+		return errors.New("keybase must be set prior to signing a transaction")
+	}
+
+	signMode := txf.SignMode()
+	if signMode == signing.SignMode_SIGN_MODE_UNSPECIFIED {
+		signMode = s.txConfig.SignModeHandler().DefaultMode()
+	}
+
+	key, err := keybase.Key(name)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	pubKey := key.GetPubKey()
+	signerData := authsigning.SignerData{
+		ChainID:       txf.ChainID(),
+		AccountNumber: txf.AccountNumber(),
+		Sequence:      txf.Sequence(),
+	}
+
+	var prevSignatures []signing.SignatureV2
+	if !overwriteSig {
+		if prevSignatures, err = txBuilder.GetTx().GetSignaturesV2(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	// the signer infos are part of the sign bytes in SIGN_MODE_DIRECT, they
+	// are set with an empty signature first.
+	if err := txBuilder.SetSignatures(signing.SignatureV2{
+		PubKey:   pubKey,
+		Data:     &signing.SingleSignatureData{SignMode: signMode},
+		Sequence: txf.Sequence(),
+	}); err != nil {
+		return errors.WithStack(err)
+	}
+
+	bytesToSign, err := s.txConfig.SignModeHandler().GetSignBytes(signMode, signerData, txBuilder.GetTx())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	sigBytes, _, err := keybase.Sign(name, bytesToSign)
+	if err != nil {
+		return errors.WithStack(err)
+	}
 
-func (signer) Sign(txf tx.Factory, name string, txBuilder client.TxBuilder, overwriteSig bool) error {
-	return tx.Sign(txf, name, txBuilder, overwriteSig)
+	sig := signing.SignatureV2{
+		PubKey: pubKey,
+		Data: &signing.SingleSignatureData{
+			SignMode:  signMode,
+			Signature: sigBytes,
+		},
+		Sequence: txf.Sequence(),
+	}
+	if overwriteSig {
+		return errors.WithStack(txBuilder.SetSignatures(sig))
+	}
+	return errors.WithStack(txBuilder.SetSignatures(append(prevSignatures, sig)...))
 }
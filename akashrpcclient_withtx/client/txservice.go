@@ -2,11 +2,15 @@ package client
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/tx"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	"github.com/pkg/errors"
+	tmtypes "github.com/tendermint/tendermint/types"
 )
 
 type TxService struct {
@@ -14,40 +18,94 @@ type TxService struct {
 	clientContext client.Context
 	txBuilder     client.TxBuilder
 	txFactory     tx.Factory
+	info          *TxInfo
+	watchOnly     bool
 }
 
+// ErrWatchOnlyAccount is returned when a tx of a watch-only account is signed.
+var ErrWatchOnlyAccount = errors.New("cannot sign with a watch-only account")
+
 // Broadcast signs and broadcasts this tx.
 // If faucet is enabled and if the "from" account doesn't have enough funds, is
 // it automatically filled with the default amount, and the tx is broadcasted
 // again. Note that this may still end with the same error if the amount is
 // greater than the amount dumped by the faucet.
 func (s TxService) Broadcast(ctx context.Context) (Response, error) {
-	defer s.client.lockBech32Prefix()()
+	resp, err := s.broadcast(ctx)
+	if err != nil {
+		return Response{}, err
+	}
+
+	return s.waitForInclusion(ctx, resp.TxHash)
+}
+
+// broadcast signs this tx and broadcasts it without waiting for its inclusion
+// in a block. Errors raised before the tx is sent to the node are
+// TxNotAcceptedErrors.
+func (s TxService) broadcast(ctx context.Context) (*sdktypes.TxResponse, error) {
+	resp, sent, err := s.signAndBroadcast(ctx)
+	if err != nil && !sent {
+		return nil, notAccepted(err)
+	}
+	return resp, err
+}
+
+// signAndBroadcast signs and broadcasts this tx, sent reports whether it was
+// sent to the node.
+func (s TxService) signAndBroadcast(ctx context.Context) (resp *sdktypes.TxResponse, sent bool, err error) {
+	if s.watchOnly {
+		return nil, false, ErrWatchOnlyAccount
+	}
+
+	if err := s.client.validateMsgs(s.txBuilder.GetTx().GetMsgs()...); err != nil {
+		return nil, false, err
+	}
 
-	// validate msgs.
-	for _, msg := range s.txBuilder.GetTx().GetMsgs() {
-		if err := msg.ValidateBasic(); err != nil {
-			return Response{}, errors.WithStack(err)
-		}
+	if err := s.client.interceptors.beforeSign(ctx, s.info); err != nil {
+		return nil, false, s.abort(ctx, err)
 	}
 
 	accountName := s.clientContext.GetFromName()
 	if err := s.client.signer.Sign(s.txFactory, accountName, s.txBuilder, true); err != nil {
-		return Response{}, errors.WithStack(err)
+		return nil, false, s.abort(ctx, errors.WithStack(err))
 	}
 
 	txBytes, err := s.clientContext.TxConfig.TxEncoder()(s.txBuilder.GetTx())
 	if err != nil {
-		return Response{}, errors.WithStack(err)
+		return nil, false, s.abort(ctx, errors.WithStack(err))
 	}
 
-	resp, err := s.clientContext.BroadcastTx(txBytes)
-	if err := handleBroadcastResult(resp, err); err != nil {
-		return Response{}, err
+	s.info.TxBytes = txBytes
+	s.info.TxHash = fmt.Sprintf("%X", tmtypes.Tx(txBytes).Hash())
+
+	if err := s.client.interceptors.afterSign(ctx, s.info); err != nil {
+		return nil, false, s.abort(ctx, err)
+	}
+
+	resp, err = s.clientContext.BroadcastTx(txBytes)
+	err = classifyBroadcastError(resp, handleBroadcastResult(resp, err))
+	s.client.interceptors.afterBroadcast(ctx, s.client.logger, s.info, resp, err)
+	if err != nil {
+		return nil, true, err
 	}
 
-	res, err := s.client.WaitForTx(ctx, resp.TxHash)
+	return resp, true, nil
+}
+
+// abort runs the AfterBroadcast hooks for a tx which failed before being sent
+// to the node, so that the interceptors release what BeforeSign reserved, and
+// returns err as a TxNotAcceptedError.
+func (s TxService) abort(ctx context.Context, err error) error {
+	err = notAccepted(err)
+	s.client.interceptors.afterBroadcast(ctx, s.client.logger, s.info, nil, err)
+	return err
+}
+
+// waitForInclusion waits for the broadcasted tx to be included in a block.
+func (s TxService) waitForInclusion(ctx context.Context, hash string) (Response, error) {
+	res, err := s.client.WaitForTx(ctx, hash)
 	if err != nil {
+		s.client.interceptors.afterInclusion(ctx, s.client.logger, s.info, Response{}, err)
 		return Response{}, err
 	}
 	// NOTE(tb) second and third parameters are omitted:
@@ -57,10 +115,42 @@ func (s TxService) Broadcast(ctx context.Context) (Response, error) {
 	// - third parameter represents the timestamp of the tx, which must be
 	// fetched from the block itself. So it requires another API call to
 	// fetch the block from res.Height, not sure if it's worth it too.
-	resp = sdktypes.NewResponseResultTx(res, nil, "")
+	resp := sdktypes.NewResponseResultTx(res, nil, "")
 
-	return Response{
+	response := Response{
 		Codec:      s.clientContext.Codec,
 		TxResponse: resp,
-	}, handleBroadcastResult(resp, err)
+	}
+
+	err = handleBroadcastResult(resp, nil)
+	s.client.interceptors.afterInclusion(ctx, s.client.logger, s.info, response, err)
+	return response, err
+}
+
+// EncodeJSON returns the JSON encoding of the unsigned tx, as produced in
+// generate-only mode.
+func (s TxService) EncodeJSON() ([]byte, error) {
+	bz, err := s.clientContext.TxConfig.TxJSONEncoder()(s.txBuilder.GetTx())
+	return bz, errors.WithStack(err)
+}
+
+// setSignerInfo sets an empty signature carrying the public key and sequence
+// of the signer, so that offline signers know who has to sign the tx.
+func (s TxService) setSignerInfo(pubKey cryptotypes.PubKey) error {
+	if pubKey == nil {
+		return nil
+	}
+
+	signMode := s.txFactory.SignMode()
+	if signMode == signing.SignMode_SIGN_MODE_UNSPECIFIED {
+		signMode = s.clientContext.TxConfig.SignModeHandler().DefaultMode()
+	}
+
+	return errors.WithStack(s.txBuilder.SetSignatures(signing.SignatureV2{
+		PubKey: pubKey,
+		Data: &signing.SingleSignatureData{
+			SignMode: signMode,
+		},
+		Sequence: s.txFactory.Sequence(),
+	}))
 }
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/pkg/errors"
+)
+
+// validateMsgs runs the stateless checks of msgs. ValidateBasic decodes the
+// addresses with the SDK global bech32 config, so when the global prefixes
+// are not the ones of the client, the checks run on copies of msgs whose
+// addresses are encoded with the global prefixes.
+func (c Client) validateMsgs(msgs ...sdktypes.Msg) error {
+	prefixes := c.globalPrefixes()
+	for _, msg := range msgs {
+		checked := msg
+		if len(prefixes) != 0 {
+			var err error
+			if checked, err = c.reencodeAddresses(msg, prefixes); err != nil {
+				return err
+			}
+		}
+		if err := checked.ValidateBasic(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// globalPrefixes maps the bech32 prefixes of the client to the ones of the
+// SDK global config which differ.
+func (c Client) globalPrefixes() map[string]string {
+	config := sdktypes.GetConfig()
+	codec := c.addressCodec
+	all := map[string]string{
+		codec.AccountPrefix():                           config.GetBech32AccountAddrPrefix(),
+		codec.AccountPubPrefix():                        config.GetBech32AccountPubPrefix(),
+		codec.ValidatorPrefix():                         config.GetBech32ValidatorAddrPrefix(),
+		codec.ValidatorPrefix() + sdktypes.PrefixPublic: config.GetBech32ValidatorPubPrefix(),
+		codec.ConsensusPrefix():                         config.GetBech32ConsensusAddrPrefix(),
+		codec.ConsensusPrefix() + sdktypes.PrefixPublic: config.GetBech32ConsensusPubPrefix(),
+	}
+
+	prefixes := make(map[string]string)
+	for from, to := range all {
+		if from != to {
+			prefixes[from] = to
+		}
+	}
+	return prefixes
+}
+
+// reencodeAddresses returns a copy of msg with the bech32 strings using the
+// prefixes of the client encoded with the global prefixes. It goes through
+// the JSON encoding of msg, which covers the messages nested in Any fields
+// like the ones of authz MsgExec.
+func (c Client) reencodeAddresses(msg sdktypes.Msg, prefixes map[string]string) (sdktypes.Msg, error) {
+	typ := reflect.TypeOf(msg)
+	if typ.Kind() != reflect.Ptr {
+		return nil, errors.Errorf("cannot validate message %T, not a pointer", msg)
+	}
+
+	bz, err := c.context.Codec.MarshalJSON(msg)
+	if err != nil {
+		return nil, errors.Wrapf(err, "encoding message %T", msg)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, errors.Wrapf(err, "decoding message %T", msg)
+	}
+
+	if bz, err = json.Marshal(reencode(doc, prefixes)); err != nil {
+		return nil, errors.Wrapf(err, "encoding message %T", msg)
+	}
+
+	out := reflect.New(typ.Elem()).Interface().(sdktypes.Msg)
+	if err := c.context.Codec.UnmarshalJSON(bz, out); err != nil {
+		return nil, errors.Wrapf(err, "decoding message %T", msg)
+	}
+	return out, nil
+}
+
+func reencode(v interface{}, prefixes map[string]string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = reencode(e, prefixes)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = reencode(e, prefixes)
+		}
+	case string:
+		hrp, bz, err := bech32.DecodeAndConvert(v)
+		if err != nil {
+			return v
+		}
+		if to, ok := prefixes[hrp]; ok {
+			if s, err := bech32.ConvertAndEncode(to, bz); err == nil {
+				return s
+			}
+		}
+	}
+	return v
+}
@@ -0,0 +1,87 @@
+// Command render-sdl renders SDL templates.
+//
+//	render-sdl [-var NAME=value]... [-list] [-validate] template.yml
+//
+// It prints the rendered SDL, or the variables of the template with -list.
+// With -validate it validates the rendered SDL as validate-sdl does and
+// exits with status 1 if it has errors.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"akashrpcclient/sdltools"
+)
+
+// vars collects the repeated -var flags.
+type vars map[string]string
+
+func (v vars) String() string {
+	return ""
+}
+
+func (v vars) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("expected NAME=value, got %q", s)
+	}
+	v[name] = value
+	return nil
+}
+
+func main() {
+	values := vars{}
+	flag.Var(values, "var", "value of a variable as NAME=value, may be repeated")
+	list := flag.Bool("list", false, "list the variables of the template instead of rendering it")
+	validate := flag.Bool("validate", false, "validate the rendered SDL")
+	denoms := flag.String("denoms", "uakt", "comma separated list of the denoms pricing may use, with -validate")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] template\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	path := flag.Arg(0)
+
+	t, err := sdltools.ParseTemplateFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s:%s\n", path, err)
+		os.Exit(1)
+	}
+
+	if *list {
+		for _, v := range t.Variables() {
+			switch {
+			case v.Required():
+				fmt.Printf("%s\t%s\trequired\n", v.Name, v.Type)
+			default:
+				fmt.Printf("%s\t%s\tdefault %q\n", v.Name, v.Type, v.Default)
+			}
+		}
+		return
+	}
+
+	buf, err := t.Render(values)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", path, err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(buf)
+
+	if *validate {
+		diagnostics := sdltools.Validate(buf, sdltools.WithDenoms(strings.Split(*denoms, ",")...))
+		for _, d := range diagnostics {
+			fmt.Fprintf(os.Stderr, "%s:%s\n", path, d)
+		}
+		if sdltools.HasErrors(diagnostics) {
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Command validate-sdl validates and lints SDL files.
+//
+//	validate-sdl [-denoms uakt] deploy.yml...
+//
+// It prints one line-numbered diagnostic per line and exits with status 1 if
+// any SDL has errors.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"akashrpcclient/sdltools"
+)
+
+func main() {
+	denoms := flag.String("denoms", "uakt", "comma separated list of the denoms pricing may use")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] sdl...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	failed := false
+	for _, path := range flag.Args() {
+		diagnostics, err := sdltools.ValidateFile(path, sdltools.WithDenoms(strings.Split(*denoms, ",")...))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			failed = true
+			continue
+		}
+
+		for _, d := range diagnostics {
+			fmt.Printf("%s:%s\n", path, d)
+		}
+		if sdltools.HasErrors(diagnostics) {
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,158 @@
+package deployment
+
+import (
+	"context"
+
+	"akashrpcclient/account"
+	"akashrpcclient/client"
+
+	"github.com/akash-network/node/x/deployment/types/v1beta2"
+	etypes "github.com/akash-network/node/x/escrow/types/v1beta2"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+)
+
+// CloseResult is the outcome of Close.
+type CloseResult struct {
+	// Response is the response of the tx which closed the deployment.
+	Response client.Response
+
+	// Settled is the amount paid to the providers by the final settlement
+	// of the escrow account.
+	Settled sdktypes.DecCoin
+
+	// Refunded is the unspent escrow balance returned to the owner and the
+	// depositors.
+	Refunded sdktypes.DecCoin
+}
+
+// Close closes deployment id owned by account along with its groups and
+// leases, and waits for its escrow account to be settled and refunded.
+func (s Service) Close(ctx context.Context, account account.Account, id v1beta2.DeploymentID) (CloseResult, error) {
+	if err := s.ensureOwner(account, id); err != nil {
+		return CloseResult{}, err
+	}
+
+	before, err := s.Get(ctx, id)
+	if err != nil {
+		return CloseResult{}, err
+	}
+	if before.Deployment.State != v1beta2.DeploymentActive {
+		return CloseResult{}, errors.Wrapf(v1beta2.ErrDeploymentClosed, "deployment %s", id)
+	}
+
+	resp, err := s.client.BroadcastTx(ctx, account, &v1beta2.MsgCloseDeployment{ID: id})
+	if err != nil {
+		return CloseResult{}, err
+	}
+	result := CloseResult{Response: resp}
+
+	after, err := s.Get(ctx, id)
+	if err != nil {
+		return result, err
+	}
+	if after.Deployment.State != v1beta2.DeploymentClosed {
+		return result, errors.Errorf("deployment %s is %s after close", id, after.Deployment.State)
+	}
+	if after.EscrowAccount.State != etypes.AccountClosed {
+		return result, errors.Errorf("escrow account of deployment %s is %s after close", id, after.EscrowAccount.State)
+	}
+
+	result.Settled, result.Refunded = settlement(before.EscrowAccount, after.EscrowAccount)
+
+	return result, nil
+}
+
+// settlement returns the amounts settled and refunded by the closing of an
+// escrow account, from its state before and after the close.
+func settlement(before, after etypes.Account) (settled, refunded sdktypes.DecCoin) {
+	denom := before.Balance.Denom
+	settled = sdktypes.NewDecCoinFromDec(denom, sdktypes.ZeroDec())
+	refunded = sdktypes.NewDecCoinFromDec(denom, sdktypes.ZeroDec())
+
+	amount := func(coin sdktypes.DecCoin) sdktypes.Dec {
+		if coin.Denom != denom || coin.Amount.IsNil() {
+			return sdktypes.ZeroDec()
+		}
+		return coin.Amount
+	}
+
+	if delta := amount(after.Transferred).Sub(amount(before.Transferred)); delta.IsPositive() {
+		settled.Amount = delta
+	}
+
+	available := amount(before.Balance).Add(amount(before.Funds))
+	if remaining := available.Sub(settled.Amount); remaining.IsPositive() {
+		refunded.Amount = remaining
+	}
+
+	return settled, refunded
+}
+
+// CloseGroup closes group id of a deployment owned by account, along with its
+// order, bids and lease.
+func (s Service) CloseGroup(ctx context.Context, account account.Account, id v1beta2.GroupID) (client.Response, error) {
+	return s.changeGroup(ctx, account, id,
+		v1beta2.Group.ValidateClosable,
+		&v1beta2.MsgCloseGroup{ID: id},
+		v1beta2.GroupClosed,
+	)
+}
+
+// PauseGroup pauses group id of a deployment owned by account, closing its
+// lease until it is started again.
+func (s Service) PauseGroup(ctx context.Context, account account.Account, id v1beta2.GroupID) (client.Response, error) {
+	return s.changeGroup(ctx, account, id,
+		v1beta2.Group.ValidatePausable,
+		&v1beta2.MsgPauseGroup{ID: id},
+		v1beta2.GroupPaused,
+	)
+}
+
+// StartGroup starts paused group id of a deployment owned by account, which
+// opens a new order for it.
+func (s Service) StartGroup(ctx context.Context, account account.Account, id v1beta2.GroupID) (client.Response, error) {
+	return s.changeGroup(ctx, account, id,
+		v1beta2.Group.ValidateStartable,
+		&v1beta2.MsgStartGroup{ID: id},
+		v1beta2.GroupOpen,
+	)
+}
+
+// changeGroup checks group id against validate, broadcasts msg and confirms
+// the group reached state.
+func (s Service) changeGroup(ctx context.Context, account account.Account, id v1beta2.GroupID, validate func(v1beta2.Group) error, msg sdktypes.Msg, state v1beta2.Group_State) (client.Response, error) {
+	if err := s.ensureOwner(account, id.DeploymentID()); err != nil {
+		return client.Response{}, err
+	}
+
+	group, err := s.group(ctx, id)
+	if err != nil {
+		return client.Response{}, err
+	}
+	if err := validate(group); err != nil {
+		return client.Response{}, errors.Wrapf(err, "group %s is %s", id, group.State)
+	}
+
+	resp, err := s.client.BroadcastTx(ctx, account, msg)
+	if err != nil {
+		return client.Response{}, err
+	}
+
+	if group, err = s.group(ctx, id); err != nil {
+		return resp, err
+	}
+	if group.State != state {
+		return resp, errors.Errorf("group %s is %s, expected %s", id, group.State, state)
+	}
+
+	return resp, nil
+}
+
+func (s Service) group(ctx context.Context, id v1beta2.GroupID) (v1beta2.Group, error) {
+	resp, err := s.queryClient.Group(ctx, &v1beta2.QueryGroupRequest{ID: id})
+	if err != nil {
+		return v1beta2.Group{}, errors.Wrapf(err, "fetching group %s", id)
+	}
+	return resp.Group, nil
+}
@@ -0,0 +1,231 @@
+package deployment
+
+import (
+	"context"
+	"time"
+
+	"akashrpcclient/account"
+	"akashrpcclient/client"
+
+	"github.com/akash-network/node/sdl"
+	"github.com/akash-network/node/x/deployment/types/v1beta2"
+	mtypes "github.com/akash-network/node/x/market/types/v1beta2"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+)
+
+// DefaultDeposit is the deposit of new deployments, the minimum accepted by
+// the chain.
+var DefaultDeposit = sdktypes.NewInt64Coin("uakt", 5000000)
+
+// CreateOption configures Create.
+type CreateOption func(*createOptions)
+
+type createOptions struct {
+	deposit   sdktypes.Coin
+	depositor string
+	dseq      uint64
+	minRunway time.Duration
+}
+
+// WithDeposit sets the deposit of the deployment, DefaultDeposit by default.
+func WithDeposit(deposit sdktypes.Coin) CreateOption {
+	return func(o *createOptions) {
+		o.deposit = deposit
+	}
+}
+
+// WithDepositor sets the bech32 address of the account paying the deposit,
+// the owner by default. The depositor must have granted the owner a
+// deployment deposit authorization, see Deposit.
+func WithDepositor(depositor string) CreateOption {
+	return func(o *createOptions) {
+		o.depositor = depositor
+	}
+}
+
+// WithDSeq sets the sequence of the deployment, the latest block height by
+// default.
+func WithDSeq(dseq uint64) CreateOption {
+	return func(o *createOptions) {
+		o.dseq = dseq
+	}
+}
+
+// WithMinRunway sets the minimum time the deposit must pay for at the maximum
+// prices of the SDL and the current block time. Create still creates the
+// deployment when the deposit is lower, and reports a LowDepositError in
+// CreateResult.Warnings.
+func WithMinRunway(min time.Duration) CreateOption {
+	return func(o *createOptions) {
+		o.minRunway = min
+	}
+}
+
+// CreateResult is the outcome of Create.
+type CreateResult struct {
+	// ID is the ID of the created deployment.
+	ID v1beta2.DeploymentID
+
+	// OrderIDs are the IDs of the orders opened for the deployment groups.
+	OrderIDs []mtypes.OrderID
+
+	// Response is the response of the tx which created the deployment.
+	Response client.Response
+
+	// Warnings are the problems which did not prevent the creation, like a
+	// LowDepositError.
+	Warnings []error
+}
+
+// Create creates a deployment owned by account from the SDL in sdlBytes and
+// waits for its inclusion.
+func (s Service) Create(ctx context.Context, account account.Account, sdlBytes []byte, options ...CreateOption) (CreateResult, error) {
+	o := createOptions{
+		deposit: DefaultDeposit,
+	}
+	for _, apply := range options {
+		apply(&o)
+	}
+
+	msg, err := s.createMsg(ctx, account, sdlBytes, o)
+	if err != nil {
+		return CreateResult{}, err
+	}
+
+	var warnings []error
+	if o.minRunway > 0 {
+		err := s.checkRunway(ctx, sdlBytes, o.deposit, o.minRunway)
+		var low *LowDepositError
+		switch {
+		case errors.As(err, &low):
+			warnings = append(warnings, low)
+		case err != nil:
+			return CreateResult{}, err
+		}
+	}
+
+	resp, err := s.client.BroadcastTx(ctx, account, msg)
+	if err != nil {
+		return CreateResult{}, err
+	}
+
+	result := CreateResult{
+		ID:       msg.ID,
+		Response: resp,
+		Warnings: warnings,
+	}
+	if result.OrderIDs, err = s.orderIDs(ctx, msg.ID); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func (s Service) createMsg(ctx context.Context, account account.Account, sdlBytes []byte, o createOptions) (*v1beta2.MsgCreateDeployment, error) {
+	sdlManifest, err := sdl.Read(sdlBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading SDL")
+	}
+
+	version, err := sdl.Version(sdlManifest)
+	if err != nil {
+		return nil, errors.Wrap(err, "computing SDL version")
+	}
+
+	groups, err := sdlManifest.DeploymentGroups()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading SDL deployment groups")
+	}
+
+	owner, err := s.client.AddressCodec().BytesToString(account.AccAddress())
+	if err != nil {
+		return nil, err
+	}
+
+	dseq := o.dseq
+	if dseq == 0 {
+		height, err := s.client.LatestBlockHeight(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "fetching latest block height")
+		}
+		dseq = uint64(height)
+	}
+
+	depositor := o.depositor
+	if depositor == "" {
+		depositor = owner
+	}
+
+	msg := &v1beta2.MsgCreateDeployment{
+		ID: v1beta2.DeploymentID{
+			Owner: owner,
+			DSeq:  dseq,
+		},
+		Version:   version,
+		Groups:    make([]v1beta2.GroupSpec, 0, len(groups)),
+		Deposit:   o.deposit,
+		Depositor: depositor,
+	}
+	for _, group := range groups {
+		msg.Groups = append(msg.Groups, *group)
+	}
+
+	if err := s.validateCreate(msg); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkDepositGrant(ctx, owner, depositor, o.deposit); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// validateCreate mirrors MsgCreateDeployment.ValidateBasic, decoding the
+// addresses with the client codec instead of the SDK global config.
+func (s Service) validateCreate(msg *v1beta2.MsgCreateDeployment) error {
+	codec := s.client.AddressCodec()
+	if _, err := codec.StringToBytes(msg.ID.Owner); err != nil {
+		return errors.Wrap(err, "invalid owner")
+	}
+	if _, err := codec.StringToBytes(msg.Depositor); err != nil {
+		return errors.Wrap(err, "invalid depositor")
+	}
+
+	switch {
+	case msg.ID.DSeq == 0:
+		return errors.WithStack(v1beta2.ErrInvalidDeploymentID)
+	case len(msg.Groups) == 0:
+		return errors.WithStack(v1beta2.ErrInvalidGroups)
+	case len(msg.Version) != v1beta2.ManifestVersionLength:
+		return errors.WithStack(v1beta2.ErrInvalidVersion)
+	case !msg.Deposit.IsValid() || !msg.Deposit.IsPositive():
+		return errors.Wrapf(v1beta2.ErrInvalidDeposit, "deposit %s", msg.Deposit)
+	}
+
+	for _, group := range msg.Groups {
+		if err := group.ValidateBasic(); err != nil {
+			return errors.Wrapf(err, "invalid group %q", group.Name)
+		}
+	}
+
+	return nil
+}
+
+// orderIDs returns the IDs of the orders of deployment id.
+func (s Service) orderIDs(ctx context.Context, id v1beta2.DeploymentID) ([]mtypes.OrderID, error) {
+	orders, err := s.orders(ctx, mtypes.OrderFilters{
+		Owner: id.Owner,
+		DSeq:  id.DSeq,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]mtypes.OrderID, 0, len(orders))
+	for _, order := range orders {
+		ids = append(ids, order.OrderID)
+	}
+	return ids, nil
+}
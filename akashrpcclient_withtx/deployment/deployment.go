@@ -0,0 +1,162 @@
+// Package deployment manages the lifecycle of Akash deployments.
+package deployment
+
+import (
+	"context"
+	"time"
+
+	"akashrpcclient/account"
+	"akashrpcclient/client"
+
+	manifest "github.com/akash-network/node/manifest/v2beta1"
+	"github.com/akash-network/node/x/deployment/types/v1beta2"
+	mtypes "github.com/akash-network/node/x/market/types/v1beta2"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	"github.com/pkg/errors"
+)
+
+// ManifestSender sends the manifest of a deployment to a provider, as
+// `provider-services send-manifest` does. HTTPManifestSender implements it
+// over the REST API of providers.
+type ManifestSender interface {
+	SendManifest(ctx context.Context, provider string, dseq uint64, m manifest.Manifest) error
+}
+
+// Option configures your service.
+type Option func(*Service)
+
+// WithManifestSender sets the sender used to deliver updated manifests to the
+// providers of the active leases of a deployment. Manifests are not sent when
+// no sender is set.
+func WithManifestSender(sender ManifestSender) Option {
+	return func(s *Service) {
+		s.manifestSender = sender
+	}
+}
+
+// Service creates and manages the deployments of the accounts of a client.
+type Service struct {
+	client client.Client
+
+	queryClient       v1beta2.QueryClient
+	marketQueryClient mtypes.QueryClient
+	authzQueryClient  authz.QueryClient
+
+	// blockTime returns the time of the latest block.
+	blockTime func(ctx context.Context) (time.Time, error)
+
+	manifestSender ManifestSender
+}
+
+// New creates a new deployment service using c to query and broadcast.
+func New(c client.Client, options ...Option) Service {
+	s := Service{
+		client:            c,
+		queryClient:       v1beta2.NewQueryClient(c.Context()),
+		marketQueryClient: mtypes.NewQueryClient(c.Context()),
+		authzQueryClient:  authz.NewQueryClient(c.Context()),
+		blockTime: func(ctx context.Context) (time.Time, error) {
+			return c.BlockTime(ctx, 0)
+		},
+	}
+
+	for _, apply := range options {
+		apply(&s)
+	}
+
+	return s
+}
+
+// Get returns the deployment id along with its groups and escrow account.
+func (s Service) Get(ctx context.Context, id v1beta2.DeploymentID) (*v1beta2.QueryDeploymentResponse, error) {
+	resp, err := s.queryClient.Deployment(ctx, &v1beta2.QueryDeploymentRequest{ID: id})
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching deployment %s", id)
+	}
+	return resp, nil
+}
+
+// activeLeases returns the active leases of deployment id.
+func (s Service) activeLeases(ctx context.Context, id v1beta2.DeploymentID) ([]mtypes.Lease, error) {
+	return s.leases(ctx, mtypes.LeaseFilters{
+		Owner: id.Owner,
+		DSeq:  id.DSeq,
+		State: mtypes.LeaseActive.String(),
+	})
+}
+
+func (s Service) orders(ctx context.Context, filters mtypes.OrderFilters) ([]mtypes.Order, error) {
+	var orders []mtypes.Order
+	for pageReq := (&query.PageRequest{}); pageReq != nil; {
+		resp, err := s.marketQueryClient.Orders(ctx, &mtypes.QueryOrdersRequest{
+			Filters:    filters,
+			Pagination: pageReq,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching orders of %s", filters.Owner)
+		}
+		orders = append(orders, resp.Orders...)
+		pageReq = nextPage(resp.Pagination)
+	}
+
+	return orders, nil
+}
+
+func (s Service) bids(ctx context.Context, filters mtypes.BidFilters) ([]mtypes.Bid, error) {
+	var bids []mtypes.Bid
+	for pageReq := (&query.PageRequest{}); pageReq != nil; {
+		resp, err := s.marketQueryClient.Bids(ctx, &mtypes.QueryBidsRequest{
+			Filters:    filters,
+			Pagination: pageReq,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching bids of %s", filters.Owner)
+		}
+		for _, b := range resp.Bids {
+			bids = append(bids, b.Bid)
+		}
+		pageReq = nextPage(resp.Pagination)
+	}
+
+	return bids, nil
+}
+
+func (s Service) leases(ctx context.Context, filters mtypes.LeaseFilters) ([]mtypes.Lease, error) {
+	var leases []mtypes.Lease
+	for pageReq := (&query.PageRequest{}); pageReq != nil; {
+		resp, err := s.marketQueryClient.Leases(ctx, &mtypes.QueryLeasesRequest{
+			Filters:    filters,
+			Pagination: pageReq,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching leases of %s", filters.Owner)
+		}
+		for _, l := range resp.Leases {
+			leases = append(leases, l.Lease)
+		}
+		pageReq = nextPage(resp.Pagination)
+	}
+
+	return leases, nil
+}
+
+// ensureOwner returns an error if account does not own deployment id.
+func (s Service) ensureOwner(account account.Account, id v1beta2.DeploymentID) error {
+	owner, err := s.client.AddressCodec().BytesToString(account.AccAddress())
+	if err != nil {
+		return err
+	}
+	if owner != id.Owner {
+		return errors.Errorf("account %s does not own deployment %s", owner, id)
+	}
+	return nil
+}
+
+// nextPage returns the request of the page following resp, nil on the last page.
+func nextPage(resp *query.PageResponse) *query.PageRequest {
+	if resp == nil || len(resp.NextKey) == 0 {
+		return nil
+	}
+	return &query.PageRequest{Key: resp.NextKey}
+}
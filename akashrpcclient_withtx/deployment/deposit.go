@@ -0,0 +1,133 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+
+	"akashrpcclient/account"
+	"akashrpcclient/client"
+
+	"github.com/akash-network/node/x/deployment/types/v1beta2"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DepositGrantError is returned when a third-party depositor has not granted
+// the owner of a deployment a DepositDeploymentAuthorization covering a
+// deposit.
+type DepositGrantError struct {
+	Depositor string
+	Owner     string
+	Amount    sdktypes.Coin
+	Reason    string
+}
+
+func (e *DepositGrantError) Error() string {
+	return fmt.Sprintf("depositor %s cannot deposit %s on behalf of %s: %s", e.Depositor, e.Amount, e.Owner, e.Reason)
+}
+
+// Deposit deposits amount into the escrow account of deployment id owned by
+// account and waits for its inclusion. The deposit is paid by depositor, the
+// owner when empty. A depositor other than the owner must have granted the
+// owner a DepositDeploymentAuthorization with a spend limit covering amount,
+// which is checked before broadcasting. Like client.Client.BroadcastTx, it
+// fails with a client.TxNotAcceptedError when no deposit can have been made.
+func (s Service) Deposit(ctx context.Context, account account.Account, id v1beta2.DeploymentID, amount sdktypes.Coin, depositor string) (client.Response, error) {
+	if depositor == "" {
+		depositor = id.Owner
+	}
+	if err := s.checkDeposit(ctx, account, id, amount, depositor); err != nil {
+		return client.Response{}, &client.TxNotAcceptedError{Err: err}
+	}
+
+	return s.client.BroadcastTx(ctx, account, &v1beta2.MsgDepositDeployment{
+		ID:        id,
+		Amount:    amount,
+		Depositor: depositor,
+	})
+}
+
+// checkDeposit checks the deposit of amount by depositor into deployment id
+// before it is broadcast.
+func (s Service) checkDeposit(ctx context.Context, account account.Account, id v1beta2.DeploymentID, amount sdktypes.Coin, depositor string) error {
+	if err := s.ensureOwner(account, id); err != nil {
+		return err
+	}
+
+	if !amount.IsValid() || !amount.IsPositive() {
+		return errors.Wrapf(v1beta2.ErrInvalidDeposit, "deposit %s", amount)
+	}
+
+	if _, err := s.client.AddressCodec().StringToBytes(depositor); err != nil {
+		return errors.Wrap(err, "invalid depositor")
+	}
+
+	current, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if current.Deployment.State != v1beta2.DeploymentActive {
+		return errors.Wrapf(v1beta2.ErrDeploymentClosed, "deployment %s", id)
+	}
+	if denom := current.EscrowAccount.Balance.Denom; denom != "" && denom != amount.Denom {
+		return errors.Wrapf(v1beta2.ErrInvalidDeposit, "deployment %s is funded in %s, got %s", id, denom, amount)
+	}
+
+	return s.checkDepositGrant(ctx, id.Owner, depositor, amount)
+}
+
+// checkDepositGrant returns a DepositGrantError if depositor, when it is not
+// owner, has no unexpired deposit authorization for owner covering amount.
+func (s Service) checkDepositGrant(ctx context.Context, owner, depositor string, amount sdktypes.Coin) error {
+	if depositor == owner {
+		return nil
+	}
+
+	grantErr := func(reason string) error {
+		return &DepositGrantError{
+			Depositor: depositor,
+			Owner:     owner,
+			Amount:    amount,
+			Reason:    reason,
+		}
+	}
+
+	resp, err := s.authzQueryClient.Grants(ctx, &authz.QueryGrantsRequest{
+		Granter:    depositor,
+		Grantee:    owner,
+		MsgTypeUrl: sdktypes.MsgTypeURL(&v1beta2.MsgDepositDeployment{}),
+	})
+	// the authz module answers NotFound when there is no grant.
+	if status.Code(errors.Cause(err)) == codes.NotFound || (err == nil && len(resp.Grants) == 0) {
+		return grantErr("no deposit authorization granted")
+	}
+	if err != nil {
+		return errors.Wrapf(err, "fetching deposit grants of %s to %s", depositor, owner)
+	}
+
+	now, err := s.blockTime(ctx)
+	if err != nil {
+		return err
+	}
+
+	reason := "no deposit authorization granted"
+	for _, grant := range resp.Grants {
+		auth, ok := grant.GetAuthorization().(*v1beta2.DepositDeploymentAuthorization)
+		switch {
+		case !ok:
+			// deposits are only accepted through deployment deposit authorizations.
+			continue
+		case !grant.Expiration.IsZero() && !grant.Expiration.After(now):
+			reason = fmt.Sprintf("deposit authorization expired at %s", grant.Expiration)
+		case auth.SpendLimit.Denom != amount.Denom || auth.SpendLimit.IsLT(amount):
+			reason = fmt.Sprintf("spend limit %s does not cover the deposit", auth.SpendLimit)
+		default:
+			return nil
+		}
+	}
+
+	return grantErr(reason)
+}
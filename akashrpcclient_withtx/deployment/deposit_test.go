@@ -0,0 +1,147 @@
+package deployment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/akash-network/node/x/deployment/types/v1beta2"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testOwner     = "akash1owner"
+	testDepositor = "akash1depositor"
+)
+
+// fakeAuthzQueryClient answers Grants with grants, or NotFound when there are
+// none, and counts the queries.
+type fakeAuthzQueryClient struct {
+	authz.QueryClient
+
+	grants  []*authz.Grant
+	queries int
+}
+
+func (c *fakeAuthzQueryClient) Grants(_ context.Context, req *authz.QueryGrantsRequest, _ ...grpc.CallOption) (*authz.QueryGrantsResponse, error) {
+	c.queries++
+	if req.Granter != testDepositor || req.Grantee != testOwner {
+		return nil, status.Error(codes.NotFound, "no authorization found")
+	}
+	if len(c.grants) == 0 {
+		return nil, status.Error(codes.NotFound, "no authorization found")
+	}
+	return &authz.QueryGrantsResponse{Grants: c.grants}, nil
+}
+
+func TestCheckDepositGrant(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	amount := sdktypes.NewInt64Coin("uakt", 1000)
+
+	grant := func(authorization authz.Authorization, expiration time.Time) *authz.Grant {
+		g, err := authz.NewGrant(authorization, expiration)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &g
+	}
+	deposit := func(limit sdktypes.Coin) authz.Authorization {
+		return v1beta2.NewDepositDeploymentAuthorization(limit)
+	}
+
+	tests := []struct {
+		name      string
+		depositor string
+		grants    []*authz.Grant
+
+		// wantGrantErr is set when a DepositGrantError is expected.
+		wantGrantErr bool
+		wantQueries  int
+	}{
+		{
+			name:        "self-deposit",
+			depositor:   testOwner,
+			wantQueries: 0,
+		},
+		{
+			name:         "missing grant",
+			depositor:    testDepositor,
+			wantGrantErr: true,
+			wantQueries:  1,
+		},
+		{
+			name:         "expired grant",
+			depositor:    testDepositor,
+			grants:       []*authz.Grant{grant(deposit(amount), now.Add(-time.Hour))},
+			wantGrantErr: true,
+			wantQueries:  1,
+		},
+		{
+			name:         "spend limit below the amount",
+			depositor:    testDepositor,
+			grants:       []*authz.Grant{grant(deposit(sdktypes.NewInt64Coin("uakt", 999)), now.Add(time.Hour))},
+			wantGrantErr: true,
+			wantQueries:  1,
+		},
+		{
+			name:         "wrong denom",
+			depositor:    testDepositor,
+			grants:       []*authz.Grant{grant(deposit(sdktypes.NewInt64Coin("uusdc", 1000)), now.Add(time.Hour))},
+			wantGrantErr: true,
+			wantQueries:  1,
+		},
+		{
+			name:         "other authorization",
+			depositor:    testDepositor,
+			grants:       []*authz.Grant{grant(banktypes.NewSendAuthorization(sdktypes.NewCoins(amount)), now.Add(time.Hour))},
+			wantGrantErr: true,
+			wantQueries:  1,
+		},
+		{
+			name:        "covering grant",
+			depositor:   testDepositor,
+			grants:      []*authz.Grant{grant(deposit(amount), now.Add(time.Hour))},
+			wantQueries: 1,
+		},
+		{
+			name:      "covering grant among others",
+			depositor: testDepositor,
+			grants: []*authz.Grant{
+				grant(deposit(amount), now.Add(-time.Hour)),
+				grant(deposit(sdktypes.NewInt64Coin("uakt", 2000)), time.Time{}),
+			},
+			wantQueries: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queryClient := &fakeAuthzQueryClient{grants: tt.grants}
+			s := Service{
+				authzQueryClient: queryClient,
+				blockTime: func(context.Context) (time.Time, error) {
+					return now, nil
+				},
+			}
+
+			err := s.checkDepositGrant(context.Background(), testOwner, tt.depositor, amount)
+
+			var grantErr *DepositGrantError
+			switch {
+			case tt.wantGrantErr && !errors.As(err, &grantErr):
+				t.Errorf("checkDepositGrant() error = %v, want a DepositGrantError", err)
+			case !tt.wantGrantErr && err != nil:
+				t.Errorf("checkDepositGrant() error = %v, want nil", err)
+			}
+			if queryClient.queries != tt.wantQueries {
+				t.Errorf("checkDepositGrant() queried grants %d times, want %d", queryClient.queries, tt.wantQueries)
+			}
+		})
+	}
+}
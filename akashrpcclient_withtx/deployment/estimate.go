@@ -0,0 +1,85 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"akashrpcclient/sdltools"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+// CostEstimate is the cost estimate of an SDL at the measured block time.
+type CostEstimate struct {
+	sdltools.Estimate
+
+	// BlockTime is the measured average block time.
+	BlockTime time.Duration
+}
+
+// DepositFor returns the deposit paying for period at the maximum prices.
+func (e CostEstimate) DepositFor(period time.Duration) sdktypes.Coins {
+	return e.Deposit(period, e.BlockTime)
+}
+
+// RunwayOf returns the time deposit pays for at the maximum prices, see
+// sdltools.Estimate.Runway.
+func (e CostEstimate) RunwayOf(deposit sdktypes.Coin) (time.Duration, error) {
+	return e.Runway(deposit, e.BlockTime)
+}
+
+// LowDepositError is reported by Create when the deposit pays for less than
+// the minimum runway at the maximum prices of the SDL.
+type LowDepositError struct {
+	Deposit sdktypes.Coin
+	Runway  time.Duration
+	Min     time.Duration
+
+	// Required is the deposit paying for Min.
+	Required sdktypes.Coins
+}
+
+func (e *LowDepositError) Error() string {
+	return fmt.Sprintf("deposit %s pays for %s at most, less than %s, which requires %s",
+		e.Deposit, e.Runway, e.Min, e.Required)
+}
+
+// Estimate computes the cost estimate of the SDL in sdlBytes at the current
+// average block time.
+func (s Service) Estimate(ctx context.Context, sdlBytes []byte) (CostEstimate, error) {
+	e, err := sdltools.EstimateCost(sdlBytes)
+	if err != nil {
+		return CostEstimate{}, err
+	}
+
+	blockTime, err := s.client.AverageBlockTime(ctx, blockTimeWindow)
+	if err != nil {
+		return CostEstimate{}, err
+	}
+
+	return CostEstimate{e, blockTime}, nil
+}
+
+// checkRunway returns a LowDepositError when deposit pays for less than min
+// of the SDL in sdlBytes.
+func (s Service) checkRunway(ctx context.Context, sdlBytes []byte, deposit sdktypes.Coin, min time.Duration) error {
+	e, err := s.Estimate(ctx, sdlBytes)
+	if err != nil {
+		return err
+	}
+
+	runway, err := e.RunwayOf(deposit)
+	if err != nil {
+		return err
+	}
+	if runway >= min {
+		return nil
+	}
+	return &LowDepositError{
+		Deposit:  deposit,
+		Runway:   runway,
+		Min:      min,
+		Required: e.DepositFor(min),
+	}
+}
@@ -0,0 +1,204 @@
+package deployment
+
+import (
+	"context"
+
+	"github.com/akash-network/node/x/deployment/types/v1beta2"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/pkg/errors"
+)
+
+const defaultPageSize = 100
+
+// ListOption configures List.
+type ListOption func(*listOptions)
+
+type listOptions struct {
+	owner    string
+	state    v1beta2.Deployment_State
+	minDSeq  uint64
+	maxDSeq  uint64
+	pageSize uint64
+}
+
+// WithOwner lists only the deployments of owner.
+func WithOwner(owner string) ListOption {
+	return func(o *listOptions) {
+		o.owner = owner
+	}
+}
+
+// WithState lists only the deployments in state, v1beta2.DeploymentActive or
+// v1beta2.DeploymentClosed.
+func WithState(state v1beta2.Deployment_State) ListOption {
+	return func(o *listOptions) {
+		o.state = state
+	}
+}
+
+// WithDSeqRange lists only the deployments with a DSeq between min and max
+// included, 0 leaves a bound open.
+func WithDSeqRange(min, max uint64) ListOption {
+	return func(o *listOptions) {
+		o.minDSeq = min
+		o.maxDSeq = max
+	}
+}
+
+// WithPageSize sets the number of deployments fetched per request.
+func WithPageSize(size uint64) ListOption {
+	return func(o *listOptions) {
+		o.pageSize = size
+	}
+}
+
+// Iterator iterates over the deployments matching the filters of List, in
+// the order of the chain store. It fetches pages lazily as it advances,
+// following the next page key of each page, so that deployments created or
+// closed during the iteration do not shift the following pages.
+//
+//	it := s.List(ctx, deployment.WithOwner(owner))
+//	defer it.Close()
+//	for it.Next() {
+//		d := it.Deployment()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type Iterator struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	queryClient v1beta2.QueryClient
+	o           listOptions
+
+	done bool
+	err  error
+
+	page    []v1beta2.QueryDeploymentResponse
+	current v1beta2.QueryDeploymentResponse
+
+	// nextReq is the request of the next page, nil after the last page.
+	nextReq *query.PageRequest
+}
+
+// List returns an iterator over the deployments matching options, all the
+// deployments of the chain by default. The iterator must be closed.
+func (s Service) List(ctx context.Context, options ...ListOption) *Iterator {
+	o := listOptions{
+		pageSize: defaultPageSize,
+	}
+	for _, apply := range options {
+		apply(&o)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	return &Iterator{
+		ctx:         ctx,
+		cancel:      cancel,
+		queryClient: s.queryClient,
+		o:           o,
+		nextReq:     &query.PageRequest{Limit: o.pageSize},
+	}
+}
+
+// ListAll returns every deployment matching options.
+func (s Service) ListAll(ctx context.Context, options ...ListOption) ([]v1beta2.QueryDeploymentResponse, error) {
+	it := s.List(ctx, options...)
+	defer it.Close()
+
+	var deployments []v1beta2.QueryDeploymentResponse
+	for it.Next() {
+		deployments = append(deployments, it.Deployment())
+	}
+	return deployments, it.Err()
+}
+
+// Next advances the iterator to the next deployment. It returns false when
+// the iteration is over or failed, see Err.
+func (it *Iterator) Next() bool {
+	for {
+		for len(it.page) != 0 {
+			d := it.page[0]
+			it.page = it.page[1:]
+
+			dseq := d.Deployment.DeploymentID.DSeq
+			if dseq < it.o.minDSeq || (it.o.maxDSeq != 0 && dseq > it.o.maxDSeq) {
+				continue
+			}
+
+			it.current = d
+			return true
+		}
+
+		if it.done {
+			return false
+		}
+		if err := it.fetch(); err != nil {
+			it.finish(err)
+			return false
+		}
+	}
+}
+
+// Deployment returns the current deployment.
+func (it *Iterator) Deployment() v1beta2.QueryDeploymentResponse {
+	return it.current
+}
+
+// Err returns the error which ended the iteration, if any.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Close ends the iteration.
+func (it *Iterator) Close() {
+	it.finish(nil)
+}
+
+func (it *Iterator) finish(err error) {
+	it.done = true
+	it.page = nil
+	if it.err == nil {
+		it.err = err
+	}
+	it.cancel()
+}
+
+func (it *Iterator) fetch() error {
+	if it.nextReq == nil {
+		it.done = true
+		return nil
+	}
+
+	resp, err := it.request(it.ctx, it.nextReq)
+	if err != nil {
+		return err
+	}
+	it.page = resp.Deployments
+	it.nextReq = nextPage(resp.Pagination)
+	if it.nextReq != nil {
+		it.nextReq.Limit = it.o.pageSize
+	}
+	return nil
+}
+
+func (it *Iterator) request(ctx context.Context, pageReq *query.PageRequest) (*v1beta2.QueryDeploymentsResponse, error) {
+	filters := v1beta2.DeploymentFilters{
+		Owner: it.o.owner,
+	}
+	if it.o.state != v1beta2.DeploymentStateInvalid {
+		filters.State = it.o.state.String()
+	}
+	if it.o.minDSeq != 0 && it.o.minDSeq == it.o.maxDSeq {
+		filters.DSeq = it.o.minDSeq
+	}
+
+	resp, err := it.queryClient.Deployments(ctx, &v1beta2.QueryDeploymentsRequest{
+		Filters:    filters,
+		Pagination: pageReq,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching deployments")
+	}
+	return resp, nil
+}
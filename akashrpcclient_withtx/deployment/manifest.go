@@ -0,0 +1,175 @@
+package deployment
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"akashrpcclient/client"
+
+	manifest "github.com/akash-network/node/manifest/v2beta1"
+	ctypes "github.com/akash-network/node/x/cert/types/v1beta2"
+	ptypes "github.com/akash-network/node/x/provider/types/v1beta2"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultManifestTimeout = 30 * time.Second
+
+	// maxErrorBodySize caps the part of the body of a failed response kept in
+	// a ManifestRejectedError.
+	maxErrorBodySize = 4096
+)
+
+// ManifestRejectedError is returned when a provider answers a manifest with
+// an error status.
+type ManifestRejectedError struct {
+	Provider   string
+	StatusCode int
+	Message    string
+}
+
+func (e *ManifestRejectedError) Error() string {
+	return fmt.Sprintf("provider %s rejected the manifest with status %d: %s", e.Provider, e.StatusCode, e.Message)
+}
+
+// HTTPManifestSenderOption configures an HTTPManifestSender.
+type HTTPManifestSenderOption func(*HTTPManifestSender)
+
+// WithManifestTimeout sets the timeout of a manifest request, 30 seconds by
+// default.
+func WithManifestTimeout(timeout time.Duration) HTTPManifestSenderOption {
+	return func(s *HTTPManifestSender) {
+		s.timeout = timeout
+	}
+}
+
+// HTTPManifestSender is a ManifestSender sending manifests to the REST API of
+// providers, at the host URI they registered on chain, as
+// `provider-services send-manifest` does.
+//
+// Requests are authenticated with the client certificate of the owner of the
+// deployments, which must be published on chain, e.g. with
+// `provider-services tx cert generate client` and `publish client`. The
+// certificate of a provider is trusted when it is a valid certificate the
+// provider published on chain.
+type HTTPManifestSender struct {
+	providerQueryClient ptypes.QueryClient
+	certQueryClient     ctypes.QueryClient
+
+	certificate tls.Certificate
+	timeout     time.Duration
+}
+
+// NewHTTPManifestSender creates a sender using c to look up providers and
+// their certificates, and certificate to authenticate to them.
+func NewHTTPManifestSender(c client.Client, certificate tls.Certificate, options ...HTTPManifestSenderOption) *HTTPManifestSender {
+	s := &HTTPManifestSender{
+		providerQueryClient: ptypes.NewQueryClient(c.Context()),
+		certQueryClient:     ctypes.NewQueryClient(c.Context()),
+		certificate:         certificate,
+		timeout:             defaultManifestTimeout,
+	}
+
+	for _, apply := range options {
+		apply(s)
+	}
+
+	return s
+}
+
+// SendManifest sends m for deployment dseq to provider.
+func (s *HTTPManifestSender) SendManifest(ctx context.Context, provider string, dseq uint64, m manifest.Manifest) error {
+	resp, err := s.providerQueryClient.Provider(ctx, &ptypes.QueryProviderRequest{Owner: provider})
+	if err != nil {
+		return errors.Wrapf(err, "fetching provider %s", provider)
+	}
+
+	body, err := json.Marshal(m)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	url := fmt.Sprintf("%s/deployment/%d/manifest", strings.TrimSuffix(resp.Provider.HostURI, "/"), dseq)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				Certificates: []tls.Certificate{s.certificate},
+				// providers use self-signed certificates, checked against
+				// the chain by verifyProvider instead.
+				InsecureSkipVerify: true,
+				VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+					return s.verifyProvider(ctx, provider, rawCerts)
+				},
+				MinVersion: tls.VersionTLS12,
+			},
+		},
+	}
+	defer httpClient.CloseIdleConnections()
+
+	httpResp, err := httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "sending manifest to provider %s", provider)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(httpResp.Body, maxErrorBodySize))
+		return &ManifestRejectedError{provider, httpResp.StatusCode, strings.TrimSpace(string(msg))}
+	}
+	return nil
+}
+
+// verifyProvider checks that the certificate presented by the host of
+// provider is a current certificate of provider, valid on chain.
+func (s *HTTPManifestSender) verifyProvider(ctx context.Context, provider string, rawCerts [][]byte) error {
+	if len(rawCerts) == 0 {
+		return errors.Errorf("provider %s presented no certificate", provider)
+	}
+	cert, err := x509.ParseCertificate(rawCerts[0])
+	if err != nil {
+		return errors.Wrapf(err, "parsing certificate of provider %s", provider)
+	}
+
+	if cert.Subject.CommonName != provider {
+		return errors.Errorf("certificate of provider %s is issued to %s", provider, cert.Subject.CommonName)
+	}
+	if now := time.Now(); now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return errors.Errorf("certificate of provider %s is expired or not yet valid", provider)
+	}
+
+	resp, err := s.certQueryClient.Certificates(ctx, &ctypes.QueryCertificatesRequest{
+		Filter: ctypes.CertificateFilter{
+			Owner:  provider,
+			Serial: cert.SerialNumber.String(),
+			State:  ctypes.CertificateValid.String(),
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "fetching certificates of provider %s", provider)
+	}
+	for _, c := range resp.Certificates {
+		block, _ := pem.Decode(c.Certificate.Cert)
+		if block != nil && bytes.Equal(block.Bytes, cert.Raw) {
+			return nil
+		}
+	}
+	return errors.Errorf("certificate %s of provider %s is not valid on chain", cert.SerialNumber, provider)
+}
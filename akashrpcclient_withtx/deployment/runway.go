@@ -0,0 +1,158 @@
+package deployment
+
+import (
+	"context"
+	"time"
+
+	"akashrpcclient/internal/escrow"
+
+	"github.com/akash-network/node/x/deployment/types/v1beta2"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+)
+
+// blockTimeWindow is the number of blocks the average block time is measured
+// over.
+const blockTimeWindow = 100
+
+// Runway is the projection of the depletion of the escrow account of a
+// deployment at the current burn rate of its leases.
+type Runway struct {
+	ID v1beta2.DeploymentID
+
+	// Height is the block height the runway was computed at.
+	Height int64
+
+	// Balance is the escrow balance and funds left at Height, once the blocks
+	// since the last settlement of the escrow account are paid.
+	Balance sdktypes.DecCoin
+
+	// BurnRate is the amount paid per block to the providers of the active
+	// leases. It is zero when the deployment has no active lease.
+	BurnRate sdktypes.DecCoin
+
+	// BlockTime is the measured average block time.
+	BlockTime time.Duration
+
+	// Blocks, Remaining and DepletesAt are the number of blocks, the time
+	// left and the projected time before the escrow runs out. They are zero
+	// when the deployment burns nothing.
+	Blocks     int64
+	Remaining  time.Duration
+	DepletesAt time.Time
+}
+
+// Burning reports whether the deployment pays for active leases.
+func (r Runway) Burning() bool {
+	return r.BurnRate.IsPositive()
+}
+
+// Below reports whether the escrow of a burning deployment runs out in less
+// than min.
+func (r Runway) Below(min time.Duration) bool {
+	return r.Burning() && r.Remaining < min
+}
+
+// Runway computes the runway of the escrow account of active deployment id.
+func (s Service) Runway(ctx context.Context, id v1beta2.DeploymentID) (Runway, error) {
+	d, err := s.Get(ctx, id)
+	if err != nil {
+		return Runway{}, err
+	}
+
+	blockTime, err := s.client.AverageBlockTime(ctx, blockTimeWindow)
+	if err != nil {
+		return Runway{}, err
+	}
+
+	return s.runway(ctx, *d, blockTime)
+}
+
+// LowRunways returns the runways of the active deployments of owner whose
+// escrow runs out in less than min.
+func (s Service) LowRunways(ctx context.Context, owner string, min time.Duration) ([]Runway, error) {
+	deployments, err := s.ListAll(ctx, WithOwner(owner), WithState(v1beta2.DeploymentActive))
+	if err != nil {
+		return nil, err
+	}
+	if len(deployments) == 0 {
+		return nil, nil
+	}
+
+	blockTime, err := s.client.AverageBlockTime(ctx, blockTimeWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	var low []Runway
+	for _, d := range deployments {
+		r, err := s.runway(ctx, d, blockTime)
+		if err != nil {
+			return nil, err
+		}
+		if r.Below(min) {
+			low = append(low, r)
+		}
+	}
+
+	return low, nil
+}
+
+func (s Service) runway(ctx context.Context, d v1beta2.QueryDeploymentResponse, blockTime time.Duration) (Runway, error) {
+	id := d.Deployment.DeploymentID
+	if d.Deployment.State != v1beta2.DeploymentActive {
+		return Runway{}, errors.Wrapf(v1beta2.ErrDeploymentClosed, "deployment %s", id)
+	}
+
+	height, err := s.client.LatestBlockHeight(ctx)
+	if err != nil {
+		return Runway{}, err
+	}
+
+	leases, err := s.activeLeases(ctx, id)
+	if err != nil {
+		return Runway{}, err
+	}
+
+	escrowAccount := d.EscrowAccount
+	denom := escrowAccount.Balance.Denom
+	if denom == "" {
+		return Runway{}, errors.Errorf("escrow account of deployment %s has no balance", id)
+	}
+	amount := func(coin sdktypes.DecCoin) sdktypes.Dec {
+		if coin.Denom != denom || coin.Amount.IsNil() {
+			return sdktypes.ZeroDec()
+		}
+		return coin.Amount
+	}
+
+	rate := sdktypes.ZeroDec()
+	for _, lease := range leases {
+		rate = rate.Add(amount(lease.Price))
+	}
+
+	balance := amount(escrowAccount.Balance).Add(amount(escrowAccount.Funds))
+	if unsettled := height - escrowAccount.SettledAt; unsettled > 0 {
+		balance = balance.Sub(rate.MulInt64(unsettled))
+	}
+	if balance.IsNegative() {
+		balance = sdktypes.ZeroDec()
+	}
+
+	r := Runway{
+		ID:        id,
+		Height:    height,
+		Balance:   sdktypes.NewDecCoinFromDec(denom, balance),
+		BurnRate:  sdktypes.NewDecCoinFromDec(denom, rate),
+		BlockTime: blockTime,
+	}
+	if !r.Burning() {
+		return r, nil
+	}
+
+	r.Blocks = escrow.Blocks(balance, rate)
+	r.Remaining = escrow.Duration(r.Blocks, blockTime)
+	r.DepletesAt = time.Now().Add(r.Remaining)
+
+	return r, nil
+}
@@ -0,0 +1,204 @@
+package deployment
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"akashrpcclient/account"
+	"akashrpcclient/client"
+
+	"github.com/akash-network/node/sdl"
+	"github.com/akash-network/node/x/deployment/types/v1beta2"
+	"github.com/pkg/errors"
+)
+
+// UpdateResult is the outcome of Update.
+type UpdateResult struct {
+	// Version is the new version of the deployment.
+	Version []byte
+
+	// Response is the response of the tx which updated the deployment.
+	Response client.Response
+
+	// Providers are the providers of the active leases which received the
+	// new manifest.
+	Providers []string
+}
+
+// GroupsChangedError is returned by Update when the resource groups of the
+// new SDL differ from the groups of the deployment. Only the manifest, such
+// as images, env and ports, can be changed by an update.
+type GroupsChangedError struct {
+	Groups []string
+}
+
+func (e *GroupsChangedError) Error() string {
+	return fmt.Sprintf("deployment groups changed: %s", strings.Join(e.Groups, ", "))
+}
+
+// ManifestError is returned by Update when the deployment was updated but
+// the manifest could not be sent to some providers.
+type ManifestError struct {
+	Errors map[string]error
+}
+
+func (e *ManifestError) Error() string {
+	providers := make([]string, 0, len(e.Errors))
+	for provider, err := range e.Errors {
+		providers = append(providers, fmt.Sprintf("%s: %s", provider, err))
+	}
+	sort.Strings(providers)
+	return fmt.Sprintf("sending manifest: %s", strings.Join(providers, "; "))
+}
+
+// Update updates deployment id owned by account to the SDL in sdlBytes and
+// waits for its inclusion. The resource groups of the SDL must be the ones of
+// the deployment. When the service has a ManifestSender, the new manifest is
+// then sent to the provider of every active lease.
+func (s Service) Update(ctx context.Context, account account.Account, id v1beta2.DeploymentID, sdlBytes []byte) (UpdateResult, error) {
+	if err := s.ensureOwner(account, id); err != nil {
+		return UpdateResult{}, err
+	}
+
+	sdlManifest, err := sdl.Read(sdlBytes)
+	if err != nil {
+		return UpdateResult{}, errors.Wrap(err, "reading SDL")
+	}
+
+	version, err := sdl.Version(sdlManifest)
+	if err != nil {
+		return UpdateResult{}, errors.Wrap(err, "computing SDL version")
+	}
+
+	groups, err := sdlManifest.DeploymentGroups()
+	if err != nil {
+		return UpdateResult{}, errors.Wrap(err, "reading SDL deployment groups")
+	}
+
+	current, err := s.Get(ctx, id)
+	if err != nil {
+		return UpdateResult{}, err
+	}
+	if current.Deployment.State != v1beta2.DeploymentActive {
+		return UpdateResult{}, errors.Wrapf(v1beta2.ErrDeploymentClosed, "deployment %s", id)
+	}
+	if bytes.Equal(current.Deployment.Version, version) {
+		return UpdateResult{}, errors.Wrapf(v1beta2.ErrInvalidVersion, "deployment %s is already at this version", id)
+	}
+	if err := compareGroups(current.Groups, groups); err != nil {
+		return UpdateResult{}, err
+	}
+
+	msg := &v1beta2.MsgUpdateDeployment{
+		ID:      id,
+		Version: version,
+	}
+	resp, err := s.client.BroadcastTx(ctx, account, msg)
+	if err != nil {
+		return UpdateResult{}, err
+	}
+
+	result := UpdateResult{
+		Version:  version,
+		Response: resp,
+	}
+	if s.manifestSender == nil {
+		return result, nil
+	}
+
+	mani, err := sdlManifest.Manifest()
+	if err != nil {
+		return result, errors.Wrap(err, "reading SDL manifest")
+	}
+
+	leases, err := s.activeLeases(ctx, id)
+	if err != nil {
+		return result, err
+	}
+
+	sent := make(map[string]bool)
+	failed := make(map[string]error)
+	for _, lease := range leases {
+		provider := lease.LeaseID.Provider
+		if _, ok := failed[provider]; ok || sent[provider] {
+			continue
+		}
+		if err := s.manifestSender.SendManifest(ctx, provider, id.DSeq, mani); err != nil {
+			failed[provider] = err
+			continue
+		}
+		sent[provider] = true
+		result.Providers = append(result.Providers, provider)
+	}
+	if len(failed) != 0 {
+		return result, &ManifestError{failed}
+	}
+
+	return result, nil
+}
+
+// compareGroups returns a GroupsChangedError if the groups of the SDL differ
+// from the groups of the deployment. Endpoints are left out of the
+// comparison as they follow the exposed ports of the manifest.
+func compareGroups(current []v1beta2.Group, specs []*v1beta2.GroupSpec) error {
+	currentSpecs := make(map[string]v1beta2.GroupSpec, len(current))
+	for _, g := range current {
+		currentSpecs[g.GroupSpec.Name] = g.GroupSpec
+	}
+
+	var changed []string
+	for _, spec := range specs {
+		cur, ok := currentSpecs[spec.Name]
+		if !ok {
+			changed = append(changed, fmt.Sprintf("%q added", spec.Name))
+			continue
+		}
+		delete(currentSpecs, spec.Name)
+
+		equal, err := sameResources(cur, *spec)
+		if err != nil {
+			return err
+		}
+		if !equal {
+			changed = append(changed, fmt.Sprintf("%q modified", spec.Name))
+		}
+	}
+	for name := range currentSpecs {
+		changed = append(changed, fmt.Sprintf("%q removed", name))
+	}
+
+	if len(changed) != 0 {
+		sort.Strings(changed)
+		return &GroupsChangedError{changed}
+	}
+	return nil
+}
+
+// sameResources reports whether a and b request the same resources with the
+// same placement requirements and pricing, regardless of their endpoints.
+func sameResources(a, b v1beta2.GroupSpec) (bool, error) {
+	abz, err := resourcesBytes(a)
+	if err != nil {
+		return false, err
+	}
+	bbz, err := resourcesBytes(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(abz, bbz), nil
+}
+
+func resourcesBytes(spec v1beta2.GroupSpec) ([]byte, error) {
+	resources := make([]v1beta2.Resource, len(spec.Resources))
+	for i, r := range spec.Resources {
+		r.Resources.Endpoints = nil
+		resources[i] = r
+	}
+	spec.Resources = resources
+
+	bz, err := spec.Marshal()
+	return bz, errors.Wrapf(err, "encoding group %q", spec.Name)
+}
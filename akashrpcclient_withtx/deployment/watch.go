@@ -0,0 +1,361 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/akash-network/node/x/deployment/types/v1beta2"
+	mtypes "github.com/akash-network/node/x/market/types/v1beta2"
+	"github.com/pkg/errors"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+const (
+	defaultWatchInterval = 30 * time.Second
+	watchBuffer          = 100
+)
+
+// Event is a change of state of a deployment, group, order, bid or lease
+// emitted by Watch.
+type Event interface {
+	// DeploymentID returns the deployment the event relates to.
+	DeploymentID() v1beta2.DeploymentID
+}
+
+// DeploymentChanged is emitted when a deployment is created or changes state.
+type DeploymentChanged struct {
+	Deployment v1beta2.Deployment
+
+	// Previous is the previous state, DeploymentStateInvalid for new deployments.
+	Previous v1beta2.Deployment_State
+}
+
+func (e DeploymentChanged) DeploymentID() v1beta2.DeploymentID {
+	return e.Deployment.DeploymentID
+}
+
+// GroupChanged is emitted when a group is created or changes state.
+type GroupChanged struct {
+	Group v1beta2.Group
+
+	// Previous is the previous state, GroupStateInvalid for new groups.
+	Previous v1beta2.Group_State
+}
+
+func (e GroupChanged) DeploymentID() v1beta2.DeploymentID {
+	return e.Group.GroupID.DeploymentID()
+}
+
+// OrderChanged is emitted when an order is created or changes state.
+type OrderChanged struct {
+	Order mtypes.Order
+
+	// Previous is the previous state, OrderStateInvalid for new orders.
+	Previous mtypes.Order_State
+}
+
+func (e OrderChanged) DeploymentID() v1beta2.DeploymentID {
+	return e.Order.OrderID.GroupID().DeploymentID()
+}
+
+// BidChanged is emitted when a bid is placed or changes state.
+type BidChanged struct {
+	Bid mtypes.Bid
+
+	// Previous is the previous state, BidStateInvalid for new bids.
+	Previous mtypes.Bid_State
+}
+
+func (e BidChanged) DeploymentID() v1beta2.DeploymentID {
+	return e.Bid.BidID.DeploymentID()
+}
+
+// LeaseChanged is emitted when a lease is created or changes state.
+type LeaseChanged struct {
+	Lease mtypes.Lease
+
+	// Previous is the previous state, LeaseStateInvalid for new leases.
+	Previous mtypes.Lease_State
+}
+
+func (e LeaseChanged) DeploymentID() v1beta2.DeploymentID {
+	return e.Lease.LeaseID.DeploymentID()
+}
+
+// WatchError is emitted when the state could not be fetched or the event
+// subscription failed. Watching goes on with the next poll.
+type WatchError struct {
+	Err error
+}
+
+func (e WatchError) DeploymentID() v1beta2.DeploymentID {
+	return v1beta2.DeploymentID{}
+}
+
+func (e WatchError) Error() string {
+	return fmt.Sprintf("watching deployments: %s", e.Err)
+}
+
+// WatchOption configures Watch.
+type WatchOption func(*watchOptions)
+
+type watchOptions struct {
+	interval time.Duration
+}
+
+// WithWatchInterval sets the time between two polls of the state, 30 seconds
+// by default.
+func WithWatchInterval(interval time.Duration) WatchOption {
+	return func(o *watchOptions) {
+		o.interval = interval
+	}
+}
+
+// Watch emits the changes of state of the active deployments of owner and of
+// their groups, orders, bids and leases, until they are closed. The state is
+// fetched again on every Tendermint tx event related to owner, and polled
+// every interval to catch the changes which emit no tx event, like leases
+// closed for lack of funds. The state at the start is the baseline, no event
+// is emitted for it. The channel is closed once ctx is done.
+func (s Service) Watch(ctx context.Context, owner string, options ...WatchOption) (<-chan Event, error) {
+	o := watchOptions{
+		interval: defaultWatchInterval,
+	}
+	for _, apply := range options {
+		apply(&o)
+	}
+
+	w := &watcher{
+		service: s,
+		owner:   owner,
+		out:     make(chan Event, watchBuffer),
+	}
+	if err := w.reconcile(ctx, false); err != nil {
+		return nil, err
+	}
+
+	subscriber := fmt.Sprintf("deployment-watch-%s-%d", owner, time.Now().UnixNano())
+	query := fmt.Sprintf("tm.event='Tx' AND akash.v1.owner='%s'", owner)
+	var txEvents <-chan ctypes.ResultEvent
+	sub, err := s.client.Subscribe(ctx, subscriber, query)
+	if err != nil {
+		// polling alone still catches every change, only later.
+		w.emit(ctx, WatchError{err})
+	} else {
+		txEvents = sub.Events
+	}
+
+	go func() {
+		defer close(w.out)
+		if sub != nil {
+			defer sub.Close(context.Background())
+		}
+
+		ticker := time.NewTicker(o.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-txEvents:
+				if !ok {
+					txEvents = nil
+					w.emit(ctx, WatchError{fmt.Errorf("subscription to %q ended", query)})
+					continue
+				}
+				drain(txEvents)
+			case <-ticker.C:
+			}
+
+			if err := w.reconcile(ctx, true); err != nil && ctx.Err() == nil {
+				w.emit(ctx, WatchError{err})
+			}
+		}
+	}()
+
+	return w.out, nil
+}
+
+// drain discards the pending events, which are all covered by one reconcile.
+func drain(events <-chan ctypes.ResultEvent) {
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+// watcher tracks the deployments of owner which are not closed, with their
+// groups, and the orders, bids and leases which are not closed, by ID.
+type watcher struct {
+	service Service
+	owner   string
+	out     chan Event
+
+	deployments map[string]v1beta2.Deployment
+	groups      map[string]v1beta2.Group
+	orders      map[string]mtypes.Order
+	bids        map[string]mtypes.Bid
+	leases      map[string]mtypes.Lease
+}
+
+// reconcile fetches the state of the open deployments, orders, bids and
+// leases of the owner, and of those tracked which were closed since the
+// previous reconcile, and emits the changes when emit is set.
+func (w *watcher) reconcile(ctx context.Context, emit bool) error {
+	deployments, err := w.service.ListAll(ctx, WithOwner(w.owner), WithState(v1beta2.DeploymentActive))
+	if err != nil {
+		return err
+	}
+	var (
+		orders []mtypes.Order
+		bids   []mtypes.Bid
+		leases []mtypes.Lease
+	)
+	for _, state := range []mtypes.Order_State{mtypes.OrderOpen, mtypes.OrderActive} {
+		res, err := w.service.orders(ctx, mtypes.OrderFilters{Owner: w.owner, State: state.String()})
+		if err != nil {
+			return err
+		}
+		orders = append(orders, res...)
+	}
+	for _, state := range []mtypes.Bid_State{mtypes.BidOpen, mtypes.BidActive} {
+		res, err := w.service.bids(ctx, mtypes.BidFilters{Owner: w.owner, State: state.String()})
+		if err != nil {
+			return err
+		}
+		bids = append(bids, res...)
+	}
+	for _, state := range []mtypes.Lease_State{mtypes.LeaseActive, mtypes.LeaseInsufficientFunds} {
+		res, err := w.service.leases(ctx, mtypes.LeaseFilters{Owner: w.owner, State: state.String()})
+		if err != nil {
+			return err
+		}
+		leases = append(leases, res...)
+	}
+
+	var events []Event
+
+	deploymentStates := make(map[string]v1beta2.Deployment, len(deployments))
+	groupStates := make(map[string]v1beta2.Group)
+	for _, d := range deployments {
+		deploymentStates[d.Deployment.DeploymentID.String()] = d.Deployment
+		for _, g := range d.Groups {
+			groupStates[g.GroupID.String()] = g
+		}
+	}
+	for id, prev := range w.deployments {
+		if _, ok := deploymentStates[id]; ok {
+			continue
+		}
+		resp, err := w.service.queryClient.Deployment(ctx, &v1beta2.QueryDeploymentRequest{ID: prev.DeploymentID})
+		if err != nil {
+			return errors.Wrapf(err, "fetching deployment %s", id)
+		}
+		deployments = append(deployments, *resp)
+	}
+	for _, d := range deployments {
+		if prev := w.deployments[d.Deployment.DeploymentID.String()]; prev.State != d.Deployment.State {
+			events = append(events, DeploymentChanged{d.Deployment, prev.State})
+		}
+		for _, g := range d.Groups {
+			if prev := w.groups[g.GroupID.String()]; prev.State != g.State {
+				events = append(events, GroupChanged{g, prev.State})
+			}
+		}
+	}
+
+	orderStates := make(map[string]mtypes.Order, len(orders))
+	for _, o := range orders {
+		orderStates[o.OrderID.String()] = o
+	}
+	for id, prev := range w.orders {
+		if _, ok := orderStates[id]; ok {
+			continue
+		}
+		resp, err := w.service.marketQueryClient.Order(ctx, &mtypes.QueryOrderRequest{ID: prev.OrderID})
+		if err != nil {
+			return errors.Wrapf(err, "fetching order %s", id)
+		}
+		orders = append(orders, resp.Order)
+	}
+	for _, o := range orders {
+		if prev := w.orders[o.OrderID.String()]; prev.State != o.State {
+			events = append(events, OrderChanged{o, prev.State})
+		}
+	}
+
+	bidStates := make(map[string]mtypes.Bid, len(bids))
+	for _, b := range bids {
+		bidStates[b.BidID.String()] = b
+	}
+	for id, prev := range w.bids {
+		if _, ok := bidStates[id]; ok {
+			continue
+		}
+		resp, err := w.service.marketQueryClient.Bid(ctx, &mtypes.QueryBidRequest{ID: prev.BidID})
+		if err != nil {
+			return errors.Wrapf(err, "fetching bid %s", id)
+		}
+		bids = append(bids, resp.Bid)
+	}
+	for _, b := range bids {
+		if prev := w.bids[b.BidID.String()]; prev.State != b.State {
+			events = append(events, BidChanged{b, prev.State})
+		}
+	}
+
+	leaseStates := make(map[string]mtypes.Lease, len(leases))
+	for _, l := range leases {
+		leaseStates[l.LeaseID.String()] = l
+	}
+	for id, prev := range w.leases {
+		if _, ok := leaseStates[id]; ok {
+			continue
+		}
+		resp, err := w.service.marketQueryClient.Lease(ctx, &mtypes.QueryLeaseRequest{ID: prev.LeaseID})
+		if err != nil {
+			return errors.Wrapf(err, "fetching lease %s", id)
+		}
+		leases = append(leases, resp.Lease)
+	}
+	for _, l := range leases {
+		if prev := w.leases[l.LeaseID.String()]; prev.State != l.State {
+			events = append(events, LeaseChanged{l, prev.State})
+		}
+	}
+
+	// the closed ones are reported once and no longer tracked.
+	w.deployments = deploymentStates
+	w.groups = groupStates
+	w.orders = orderStates
+	w.bids = bidStates
+	w.leases = leaseStates
+
+	if emit {
+		for _, e := range events {
+			if !w.emit(ctx, e) {
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
+
+// emit sends e unless ctx is done first.
+func (w *watcher) emit(ctx context.Context, e Event) bool {
+	select {
+	case w.out <- e:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
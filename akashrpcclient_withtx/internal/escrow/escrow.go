@@ -0,0 +1,40 @@
+// Package escrow computes how long an escrow balance lasts at a price per
+// block.
+package escrow
+
+import (
+	"math"
+	"math/big"
+	"time"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Blocks returns the number of whole blocks balance pays for at rate per
+// block, math.MaxInt64 when it does not fit in an int64. It is 0 when rate is
+// not positive or balance is negative.
+func Blocks(balance, rate sdktypes.Dec) int64 {
+	if !rate.IsPositive() || balance.IsNegative() {
+		return 0
+	}
+
+	// both decimals have the same precision, the quotient of their integer
+	// representations is the quotient of the decimals.
+	q := new(big.Int).Quo(balance.BigInt(), rate.BigInt())
+	if !q.IsInt64() {
+		return math.MaxInt64
+	}
+	return q.Int64()
+}
+
+// Duration returns the time blocks take to be produced every blockTime, the
+// maximum duration when it overflows. It is 0 when blockTime is not positive.
+func Duration(blocks int64, blockTime time.Duration) time.Duration {
+	if blocks <= 0 || blockTime <= 0 {
+		return 0
+	}
+	if blocks > math.MaxInt64/int64(blockTime) {
+		return math.MaxInt64
+	}
+	return time.Duration(blocks) * blockTime
+}
@@ -0,0 +1,56 @@
+package escrow
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestBlocks(t *testing.T) {
+	tests := []struct {
+		name          string
+		balance, rate sdktypes.Dec
+		want          int64
+	}{
+		{"exact", sdktypes.NewDec(100), sdktypes.NewDec(10), 10},
+		{"truncated", sdktypes.NewDec(105), sdktypes.NewDec(10), 10},
+		{"fractional rate", sdktypes.NewDec(1), sdktypes.NewDecWithPrec(1, 2), 100},
+		{"zero rate", sdktypes.NewDec(100), sdktypes.ZeroDec(), 0},
+		{"negative balance", sdktypes.NewDec(-1), sdktypes.NewDec(1), 0},
+		{"overflow", sdktypes.NewDec(math.MaxInt64).MulInt64(1000), sdktypes.NewDecWithPrec(1, 18), math.MaxInt64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Blocks(tt.balance, tt.rate); got != tt.want {
+				t.Errorf("Blocks(%s, %s) = %d, want %d", tt.balance, tt.rate, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		name      string
+		blocks    int64
+		blockTime time.Duration
+		want      time.Duration
+	}{
+		{"blocks", 10, 6 * time.Second, time.Minute},
+		{"no block", 0, 6 * time.Second, 0},
+		{"no block time", 10, 0, 0},
+		// about 1.5e9 blocks of 6 seconds overflow time.Duration.
+		{"overflow", 2e9, 6 * time.Second, math.MaxInt64},
+		{"max blocks", math.MaxInt64, time.Nanosecond, math.MaxInt64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Duration(tt.blocks, tt.blockTime); got != tt.want {
+				t.Errorf("Duration(%d, %s) = %s, want %s", tt.blocks, tt.blockTime, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,342 @@
+package journal
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"akashrpcclient/client"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/pkg/errors"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Status is the state of a journaled tx.
+type Status string
+
+const (
+	// StatusPending is recorded once the tx is signed, before it is broadcast.
+	StatusPending Status = "pending"
+
+	// StatusBroadcast is recorded once the node accepted the tx in its mempool.
+	StatusBroadcast Status = "broadcast"
+
+	// StatusCommitted is recorded once the tx is included in a block with success.
+	StatusCommitted Status = "committed"
+
+	// StatusFailed is recorded when the tx was rejected or failed in a block.
+	StatusFailed Status = "failed"
+
+	// StatusExpired is recorded when a pending tx could not be found on chain
+	// before the journal expiry elapsed. It is not final, the tx may still be
+	// included, e.g. when the node which accepted it gossips it late, so
+	// Reconcile keeps looking it up.
+	StatusExpired Status = "expired"
+)
+
+const defaultExpiry = 10 * time.Minute
+
+var bucketTxs = []byte("txs")
+
+// Entry is a journaled tx.
+type Entry struct {
+	Hash      string            `json:"hash"`
+	Account   string            `json:"account"`
+	Sequence  uint64            `json:"sequence"`
+	Msgs      []json.RawMessage `json:"msgs"`
+	TxBytes   []byte            `json:"tx_bytes"`
+	Status    Status            `json:"status"`
+	Height    int64             `json:"height,omitempty"`
+	Code      uint32            `json:"code,omitempty"`
+	Log       string            `json:"log,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// Done reports whether the entry reached a final status.
+func (e Entry) Done() bool {
+	return e.Status == StatusCommitted || e.Status == StatusFailed
+}
+
+// TxFetcher fetches a tx by hash, rpcclient.Client implements it.
+type TxFetcher interface {
+	Tx(ctx context.Context, hash []byte, prove bool) (*ctypes.ResultTx, error)
+}
+
+// Option configures your journal.
+type Option func(*Journal)
+
+// WithExpiry sets how long a pending tx may be missing from chain before
+// Reconcile marks it as expired.
+func WithExpiry(expiry time.Duration) Option {
+	return func(j *Journal) {
+		j.expiry = expiry
+	}
+}
+
+// Journal records every signed tx in a bbolt file before it is broadcast, so
+// that a crashed process can find out what happened to its txs on restart.
+// It implements client.Interceptor and is installed with client.WithInterceptors.
+type Journal struct {
+	client.NopInterceptor
+
+	db     *bolt.DB
+	expiry time.Duration
+	now    func() time.Time
+}
+
+// Open opens or creates the journal file at path.
+func Open(path string, options ...Option) (*Journal, error) {
+	j := &Journal{
+		expiry: defaultExpiry,
+		now:    time.Now,
+	}
+
+	for _, apply := range options {
+		apply(j)
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening journal %q", path)
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketTxs)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, errors.WithStack(err)
+	}
+
+	j.db = db
+
+	return j, nil
+}
+
+// Close closes the journal file.
+func (j *Journal) Close() error {
+	return j.db.Close()
+}
+
+// AfterSign records the signed tx as pending.
+func (j *Journal) AfterSign(_ context.Context, info *client.TxInfo) error {
+	msgs := make([]json.RawMessage, 0, len(info.Msgs))
+	for _, msg := range info.Msgs {
+		bz, err := codec.ProtoMarshalJSON(msg, nil)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		msgs = append(msgs, bz)
+	}
+
+	now := j.now()
+
+	return j.put(Entry{
+		Hash:      info.TxHash,
+		Account:   info.AccountName,
+		Sequence:  info.Sequence,
+		Msgs:      msgs,
+		TxBytes:   info.TxBytes,
+		Status:    StatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+}
+
+// AfterBroadcast records whether the node accepted the tx. Only a tx known
+// not to have reached the mempool, see client.NotAccepted, is failed, the
+// entry stays pending on other errors, like timeouts, since the tx may still
+// be committed and is left to Reconcile. Txs aborted before AfterSign recorded
+// them are ignored.
+func (j *Journal) AfterBroadcast(_ context.Context, info *client.TxInfo, resp *sdktypes.TxResponse, err error) error {
+	updateErr := j.update(info.TxHash, func(e *Entry) {
+		switch {
+		case resp != nil && resp.Code == sdkerrors.ErrTxInMempoolCache.ABCICode() && resp.Codespace == sdkerrors.RootCodespace:
+			e.Status = StatusBroadcast
+		case resp != nil && resp.Code > 0:
+			e.Status = StatusFailed
+			e.Code = resp.Code
+			e.Log = resp.RawLog
+		case client.NotAccepted(err):
+			e.Status = StatusFailed
+			e.Log = err.Error()
+		case err != nil:
+			e.Log = err.Error()
+		default:
+			e.Status = StatusBroadcast
+		}
+	})
+	var notFound *EntryNotFoundError
+	if client.NotAccepted(err) && errors.As(updateErr, &notFound) {
+		return nil
+	}
+	return updateErr
+}
+
+// AfterInclusion records the result of the tx in its block. The entry stays
+// in broadcast status when waiting for the tx failed, e.g. on ctx cancellation.
+func (j *Journal) AfterInclusion(_ context.Context, info *client.TxInfo, resp client.Response, err error) error {
+	if resp.TxResponse == nil {
+		return nil
+	}
+	return j.update(info.TxHash, func(e *Entry) {
+		e.Height = resp.Height
+		e.Code = resp.Code
+		e.Log = resp.RawLog
+		if resp.Code > 0 {
+			e.Status = StatusFailed
+			return
+		}
+		e.Status = StatusCommitted
+	})
+}
+
+// Get returns the entry of a tx by its hash.
+func (j *Journal) Get(hash string) (Entry, error) {
+	var e Entry
+	err := j.db.View(func(tx *bolt.Tx) error {
+		bz := tx.Bucket(bucketTxs).Get([]byte(strings.ToUpper(hash)))
+		if bz == nil {
+			return &EntryNotFoundError{hash}
+		}
+		return json.Unmarshal(bz, &e)
+	})
+	return e, err
+}
+
+// Entries returns the journaled txs, filtered by status if any is given.
+func (j *Journal) Entries(statuses ...Status) ([]Entry, error) {
+	var res []Entry
+	err := j.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketTxs).ForEach(func(_, bz []byte) error {
+			var e Entry
+			if err := json.Unmarshal(bz, &e); err != nil {
+				return err
+			}
+			if len(statuses) == 0 || hasStatus(statuses, e.Status) {
+				res = append(res, e)
+			}
+			return nil
+		})
+	})
+	return res, errors.WithStack(err)
+}
+
+// Reconcile looks up every unfinished entry on chain, expired ones included,
+// and marks it committed, failed or expired. It returns the entries which
+// changed status.
+func (j *Journal) Reconcile(ctx context.Context, fetcher TxFetcher) ([]Entry, error) {
+	pending, err := j.Entries(StatusPending, StatusBroadcast, StatusExpired)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []Entry
+	for _, e := range pending {
+		hash, err := hex.DecodeString(e.Hash)
+		if err != nil {
+			return changed, errors.Wrapf(err, "unable to decode tx hash '%s'", e.Hash)
+		}
+
+		res, err := fetcher.Tx(ctx, hash, false)
+		switch {
+		case err != nil && strings.Contains(err.Error(), "not found"):
+			if e.Status == StatusExpired || j.now().Sub(e.CreatedAt) < j.expiry {
+				continue
+			}
+			e.Status = StatusExpired
+		case err != nil:
+			return changed, errors.Wrapf(err, "fetching tx '%s'", e.Hash)
+		default:
+			e.Height = res.Height
+			e.Code = res.TxResult.Code
+			e.Log = res.TxResult.Log
+			e.Status = StatusCommitted
+			if e.Code > 0 {
+				e.Status = StatusFailed
+			}
+		}
+
+		e.UpdatedAt = j.now()
+		if err := j.put(e); err != nil {
+			return changed, err
+		}
+		changed = append(changed, e)
+	}
+
+	return changed, nil
+}
+
+// Prune deletes the finished entries last updated before t.
+func (j *Journal) Prune(t time.Time) error {
+	return j.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketTxs)
+
+		var keys [][]byte
+		if err := b.ForEach(func(k, bz []byte) error {
+			var e Entry
+			if err := json.Unmarshal(bz, &e); err != nil {
+				return err
+			}
+			if e.Done() && e.UpdatedAt.Before(t) {
+				keys = append(keys, k)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		for _, k := range keys {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (j *Journal) put(e Entry) error {
+	bz, err := json.Marshal(e)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(j.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketTxs).Put([]byte(strings.ToUpper(e.Hash)), bz)
+	}))
+}
+
+func (j *Journal) update(hash string, fn func(*Entry)) error {
+	e, err := j.Get(hash)
+	if err != nil {
+		return err
+	}
+	fn(&e)
+	e.UpdatedAt = j.now()
+	return j.put(e)
+}
+
+// EntryNotFoundError is returned when the journal has no entry for a tx hash.
+type EntryNotFoundError struct {
+	Hash string
+}
+
+func (e *EntryNotFoundError) Error() string {
+	return fmt.Sprintf("journal entry for tx %q does not exist", e.Hash)
+}
+
+func hasStatus(statuses []Status, s Status) bool {
+	for _, v := range statuses {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,272 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"akashrpcclient/client"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+var start = time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+
+// clock is the time returned by the journal, set by the tests.
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+// chain is a TxFetcher returning the txs it holds by hex hash, and a not
+// found error for the others.
+type chain map[string]*ctypes.ResultTx
+
+func (c chain) Tx(_ context.Context, hash []byte, _ bool) (*ctypes.ResultTx, error) {
+	res, ok := c[fmt.Sprintf("%X", hash)]
+	if !ok {
+		return nil, fmt.Errorf("tx (%X) not found", hash)
+	}
+	return res, nil
+}
+
+func newTestJournal(t *testing.T) (*Journal, *clock) {
+	t.Helper()
+
+	j, err := Open(filepath.Join(t.TempDir(), "journal.db"), WithExpiry(10*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { j.Close() })
+
+	c := &clock{start}
+	j.now = c.now
+	return j, c
+}
+
+func txHash(i int) string {
+	return fmt.Sprintf("%064X", i)
+}
+
+// sign records the tx of hash as AfterSign does for a signed tx.
+func sign(t *testing.T, j *Journal, hash string) *client.TxInfo {
+	t.Helper()
+
+	from := sdktypes.AccAddress("from________________")
+	info := &client.TxInfo{
+		AccountName: "main",
+		From:        from,
+		Msgs:        []sdktypes.Msg{banktypes.NewMsgSend(from, from, sdktypes.NewCoins(sdktypes.NewInt64Coin("uakt", 1)))},
+		Sequence:    3,
+		TxBytes:     []byte("tx"),
+		TxHash:      hash,
+	}
+	if err := j.AfterSign(context.Background(), info); err != nil {
+		t.Fatal(err)
+	}
+	return info
+}
+
+func status(t *testing.T, j *Journal, hash string) Status {
+	t.Helper()
+
+	e, err := j.Get(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e.Status
+}
+
+func TestAfterSign(t *testing.T) {
+	j, _ := newTestJournal(t)
+	sign(t, j, txHash(1))
+
+	e, err := j.Get(strings.ToLower(txHash(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Status != StatusPending || e.Account != "main" || e.Sequence != 3 || len(e.Msgs) != 1 || !e.CreatedAt.Equal(start) {
+		t.Errorf("AfterSign() recorded %+v", e)
+	}
+}
+
+func TestAfterBroadcast(t *testing.T) {
+	mempoolCache := &sdktypes.TxResponse{Code: sdkerrors.ErrTxInMempoolCache.ABCICode(), Codespace: sdkerrors.RootCodespace}
+	rejected := &sdktypes.TxResponse{Code: sdkerrors.ErrInsufficientFunds.ABCICode(), Codespace: sdkerrors.RootCodespace, RawLog: "insufficient funds"}
+
+	tests := []struct {
+		name string
+		resp *sdktypes.TxResponse
+		err  error
+		want Status
+	}{
+		{"accepted", &sdktypes.TxResponse{}, nil, StatusBroadcast},
+		{"in mempool cache", mempoolCache, errors.New("tx already in mempool cache"), StatusBroadcast},
+		{"rejected by CheckTx", rejected, &client.TxNotAcceptedError{Err: errors.New("insufficient funds")}, StatusFailed},
+		{"not accepted", nil, &client.TxNotAcceptedError{Err: errors.New("connection refused")}, StatusFailed},
+		{"unknown outcome", nil, errors.New("timed out waiting for tx to be included in a block"), StatusPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j, _ := newTestJournal(t)
+			info := sign(t, j, txHash(1))
+
+			if err := j.AfterBroadcast(context.Background(), info, tt.resp, tt.err); err != nil {
+				t.Fatal(err)
+			}
+
+			e, err := j.Get(info.TxHash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e.Status != tt.want {
+				t.Errorf("status = %s, want %s", e.Status, tt.want)
+			}
+			if tt.err != nil && tt.want != StatusBroadcast && e.Log == "" {
+				t.Error("the error is not logged")
+			}
+		})
+	}
+}
+
+func TestAfterBroadcastUnsigned(t *testing.T) {
+	j, _ := newTestJournal(t)
+	info := &client.TxInfo{TxHash: txHash(1)}
+
+	if err := j.AfterBroadcast(context.Background(), info, nil, &client.TxNotAcceptedError{Err: errors.New("signing failed")}); err != nil {
+		t.Errorf("AfterBroadcast() of an aborted tx = %v, want nil", err)
+	}
+
+	var notFound *EntryNotFoundError
+	if err := j.AfterBroadcast(context.Background(), info, &sdktypes.TxResponse{}, nil); !errors.As(err, &notFound) {
+		t.Errorf("AfterBroadcast() of an unknown tx = %v, want an EntryNotFoundError", err)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	j, c := newTestJournal(t)
+	ctx := context.Background()
+
+	committed, failed, missing := txHash(1), txHash(2), txHash(3)
+	for _, hash := range []string{committed, failed, missing} {
+		info := sign(t, j, hash)
+		if err := j.AfterBroadcast(ctx, info, &sdktypes.TxResponse{}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	onChain := chain{
+		committed: {Height: 10},
+		failed:    {Height: 11, TxResult: abci.ResponseDeliverTx{Code: 5, Log: "out of gas"}},
+	}
+
+	reconcile := func(want ...string) {
+		t.Helper()
+
+		changed, err := j.Reconcile(ctx, onChain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, e := range changed {
+			got = append(got, e.Hash)
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("Reconcile() changed %q, want %q", got, want)
+		}
+	}
+
+	c.t = start.Add(time.Minute)
+	reconcile(committed, failed)
+	for hash, want := range map[string]Status{committed: StatusCommitted, failed: StatusFailed, missing: StatusBroadcast} {
+		if got := status(t, j, hash); got != want {
+			t.Errorf("status of %s = %s, want %s", hash, got, want)
+		}
+	}
+
+	c.t = start.Add(11 * time.Minute)
+	reconcile(missing)
+	if got := status(t, j, missing); got != StatusExpired {
+		t.Errorf("status of a tx missing after the expiry = %s, want %s", got, StatusExpired)
+	}
+
+	// an expired tx is not updated again while it is still missing, but is
+	// still looked up.
+	reconcile()
+	onChain[missing] = &ctypes.ResultTx{Height: 20}
+	reconcile(missing)
+
+	e, err := j.Get(missing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Status != StatusCommitted || e.Height != 20 {
+		t.Errorf("expired tx included later recorded as %+v", e)
+	}
+}
+
+func TestReconcileFetchError(t *testing.T) {
+	j, _ := newTestJournal(t)
+	sign(t, j, txHash(1))
+
+	if _, err := j.Reconcile(context.Background(), failingFetcher{}); err == nil {
+		t.Error("Reconcile() succeeded with a failing fetcher")
+	}
+	if got := status(t, j, txHash(1)); got != StatusPending {
+		t.Errorf("status = %s, want %s", got, StatusPending)
+	}
+}
+
+type failingFetcher struct{}
+
+func (failingFetcher) Tx(context.Context, []byte, bool) (*ctypes.ResultTx, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestPrune(t *testing.T) {
+	j, c := newTestJournal(t)
+
+	entries := []struct {
+		status  Status
+		updated time.Time
+		pruned  bool
+	}{
+		{StatusCommitted, start, true},
+		{StatusFailed, start, true},
+		{StatusExpired, start, false},
+		{StatusPending, start, false},
+		{StatusBroadcast, start, false},
+		{StatusCommitted, start.Add(time.Hour), false},
+	}
+	for i, e := range entries {
+		c.t = e.updated
+		sign(t, j, txHash(i))
+		if err := j.update(txHash(i), func(entry *Entry) { entry.Status = e.status }); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := j.Prune(start.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, e := range entries {
+		_, err := j.Get(txHash(i))
+		var notFound *EntryNotFoundError
+		if pruned := errors.As(err, &notFound); pruned != e.pruned {
+			t.Errorf("%s entry updated at %s pruned: %t, want %t", e.status, e.updated, pruned, e.pruned)
+		}
+	}
+}
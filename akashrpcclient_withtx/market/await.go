@@ -0,0 +1,80 @@
+package market
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	mtypes "github.com/akash-network/node/x/market/types/v1beta2"
+	"github.com/pkg/errors"
+)
+
+// NotEnoughBidsError is returned by AwaitBids when fewer bids than expected
+// were placed before the timeout.
+type NotEnoughBidsError struct {
+	OrderID mtypes.OrderID
+	Want    int
+	Got     int
+}
+
+func (e *NotEnoughBidsError) Error() string {
+	return fmt.Sprintf("order %s got %d open bids out of %d", e.OrderID, e.Got, e.Want)
+}
+
+// AwaitBids polls the open bids of order id until there are at least minBids
+// of them, and returns them. Once timeout elapses, it returns the open bids
+// placed so far along with a NotEnoughBidsError, the caller may still select
+// one of them. It fails if the order is no longer open. minBids must be at
+// least 1.
+func (s Service) AwaitBids(ctx context.Context, id mtypes.OrderID, minBids int, timeout time.Duration) ([]mtypes.Bid, error) {
+	if minBids < 1 {
+		return nil, errors.Errorf("awaiting %d bids on order %s, at least 1 is required", minBids, id)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	var open []mtypes.Bid
+	for {
+		order, err := s.Order(ctx, id)
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return open, &NotEnoughBidsError{id, minBids, len(open)}
+			}
+			return nil, err
+		}
+		if order.State != mtypes.OrderOpen {
+			return nil, errors.Wrapf(mtypes.ErrOrderNotOpen, "order %s is %s", id, order.State)
+		}
+
+		bids, err := s.Bids(ctx, id)
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return open, &NotEnoughBidsError{id, minBids, len(open)}
+			}
+			return nil, err
+		}
+
+		open = open[:0]
+		for _, bid := range bids {
+			if bid.State == mtypes.BidOpen {
+				open = append(open, bid)
+			}
+		}
+		if len(open) >= minBids {
+			return open, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return open, &NotEnoughBidsError{id, minBids, len(open)}
+			}
+			return nil, errors.WithStack(ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
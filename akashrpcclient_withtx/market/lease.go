@@ -0,0 +1,82 @@
+package market
+
+import (
+	"context"
+	"time"
+
+	"akashrpcclient/account"
+	"akashrpcclient/client"
+
+	mtypes "github.com/akash-network/node/x/market/types/v1beta2"
+	"github.com/pkg/errors"
+)
+
+// LeaseResult is the outcome of Lease.
+type LeaseResult struct {
+	// Bid is the selected bid, the lease ID is Bid.BidID.LeaseID().
+	Bid mtypes.Bid
+
+	// Bids are the open bids the bid was selected among.
+	Bids []mtypes.Bid
+
+	// Response is the response of the tx which created the lease.
+	Response client.Response
+}
+
+// CreateLease accepts bid id, creating a lease with its provider, and waits
+// for its inclusion. account must own the deployment of the bid.
+func (s Service) CreateLease(ctx context.Context, account account.Account, id mtypes.BidID) (client.Response, error) {
+	owner, err := s.client.AddressCodec().BytesToString(account.AccAddress())
+	if err != nil {
+		return client.Response{}, err
+	}
+	if owner != id.Owner {
+		return client.Response{}, errors.Errorf("account %s does not own bid %s", owner, id)
+	}
+
+	return s.client.BroadcastTx(ctx, account, &mtypes.MsgCreateLease{BidID: id})
+}
+
+// Lease awaits minBids open bids on order id for up to timeout, see
+// AwaitBids, selects one of them with selector and leases it. A bid is
+// selected among the bids placed before the timeout even when there are
+// fewer than minBids. It fails with ErrNoEligibleBid when no bid was placed or
+// selected.
+func (s Service) Lease(ctx context.Context, account account.Account, id mtypes.OrderID, minBids int, timeout time.Duration, selector Selector) (LeaseResult, error) {
+	bids, err := s.AwaitBids(ctx, id, minBids, timeout)
+	var notEnough *NotEnoughBidsError
+	if err != nil && !errors.As(err, &notEnough) {
+		return LeaseResult{}, err
+	}
+	if len(bids) == 0 {
+		return LeaseResult{}, errors.Wrapf(ErrNoEligibleBid, "order %s: %s", id, err)
+	}
+
+	bid, err := selector.Select(ctx, bids)
+	if err != nil {
+		return LeaseResult{Bids: bids}, errors.Wrapf(err, "selecting bid of order %s", id)
+	}
+	if !contains(bids, bid.BidID) {
+		return LeaseResult{Bids: bids}, errors.Wrapf(ErrNoEligibleBid, "selected bid %s is not an open bid of order %s", bid.BidID, id)
+	}
+
+	resp, err := s.CreateLease(ctx, account, bid.BidID)
+	if err != nil {
+		return LeaseResult{Bid: bid, Bids: bids}, err
+	}
+
+	return LeaseResult{
+		Bid:      bid,
+		Bids:     bids,
+		Response: resp,
+	}, nil
+}
+
+func contains(bids []mtypes.Bid, id mtypes.BidID) bool {
+	for _, bid := range bids {
+		if bid.BidID.Equals(id) {
+			return true
+		}
+	}
+	return false
+}
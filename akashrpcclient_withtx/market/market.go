@@ -0,0 +1,129 @@
+// Package market queries the orders and bids of Akash deployments, selects
+// bids and leases them.
+package market
+
+import (
+	"context"
+	"time"
+
+	"akashrpcclient/client"
+
+	atypes "github.com/akash-network/node/x/audit/types/v1beta2"
+	dtypes "github.com/akash-network/node/x/deployment/types/v1beta2"
+	mtypes "github.com/akash-network/node/x/market/types/v1beta2"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"github.com/pkg/errors"
+)
+
+const defaultPollInterval = 5 * time.Second
+
+// Option configures the service.
+type Option func(*Service)
+
+// WithPollInterval sets the time between two queries of the bids while
+// awaiting them, 5 seconds by default.
+func WithPollInterval(interval time.Duration) Option {
+	return func(s *Service) {
+		s.pollInterval = interval
+	}
+}
+
+// Service queries the market of the chain of a client.
+type Service struct {
+	client client.Client
+
+	queryClient      mtypes.QueryClient
+	auditQueryClient atypes.QueryClient
+
+	pollInterval time.Duration
+}
+
+// New creates a new market service using c to query and broadcast.
+func New(c client.Client, options ...Option) Service {
+	s := Service{
+		client:           c,
+		queryClient:      mtypes.NewQueryClient(c.Context()),
+		auditQueryClient: atypes.NewQueryClient(c.Context()),
+		pollInterval:     defaultPollInterval,
+	}
+
+	for _, apply := range options {
+		apply(&s)
+	}
+
+	return s
+}
+
+// Orders returns the orders of deployment id, one per group and per time the
+// group was started.
+func (s Service) Orders(ctx context.Context, id dtypes.DeploymentID) ([]mtypes.Order, error) {
+	var orders []mtypes.Order
+	for pageReq := (&query.PageRequest{}); pageReq != nil; {
+		resp, err := s.queryClient.Orders(ctx, &mtypes.QueryOrdersRequest{
+			Filters: mtypes.OrderFilters{
+				Owner: id.Owner,
+				DSeq:  id.DSeq,
+			},
+			Pagination: pageReq,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching orders of deployment %s", id)
+		}
+		orders = append(orders, resp.Orders...)
+		pageReq = nextPage(resp.Pagination)
+	}
+
+	return orders, nil
+}
+
+// Order returns order id.
+func (s Service) Order(ctx context.Context, id mtypes.OrderID) (mtypes.Order, error) {
+	resp, err := s.queryClient.Order(ctx, &mtypes.QueryOrderRequest{ID: id})
+	if err != nil {
+		return mtypes.Order{}, errors.Wrapf(err, "fetching order %s", id)
+	}
+	return resp.Order, nil
+}
+
+// Bids returns the bids placed on order id, in any state. The provider of a
+// bid is its BidID.Provider.
+func (s Service) Bids(ctx context.Context, id mtypes.OrderID) ([]mtypes.Bid, error) {
+	var bids []mtypes.Bid
+	for pageReq := (&query.PageRequest{}); pageReq != nil; {
+		resp, err := s.queryClient.Bids(ctx, &mtypes.QueryBidsRequest{
+			Filters: mtypes.BidFilters{
+				Owner: id.Owner,
+				DSeq:  id.DSeq,
+				GSeq:  id.GSeq,
+				OSeq:  id.OSeq,
+			},
+			Pagination: pageReq,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching bids of order %s", id)
+		}
+		for _, b := range resp.Bids {
+			bids = append(bids, b.Bid)
+		}
+		pageReq = nextPage(resp.Pagination)
+	}
+
+	return bids, nil
+}
+
+// Bid returns bid id.
+func (s Service) Bid(ctx context.Context, id mtypes.BidID) (mtypes.Bid, error) {
+	resp, err := s.queryClient.Bid(ctx, &mtypes.QueryBidRequest{ID: id})
+	if err != nil {
+		return mtypes.Bid{}, errors.Wrapf(err, "fetching bid %s", id)
+	}
+	return resp.Bid, nil
+}
+
+// nextPage returns the request of the page following resp, nil on the last page.
+func nextPage(resp *query.PageResponse) *query.PageRequest {
+	if resp == nil || len(resp.NextKey) == 0 {
+		return nil
+	}
+	return &query.PageRequest{Key: resp.NextKey}
+}
@@ -0,0 +1,137 @@
+package market
+
+import (
+	"context"
+	"sort"
+
+	"github.com/akash-network/node/types/v1beta2"
+	atypes "github.com/akash-network/node/x/audit/types/v1beta2"
+	mtypes "github.com/akash-network/node/x/market/types/v1beta2"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrNoEligibleBid is returned by selectors when no bid meets their criteria.
+var ErrNoEligibleBid = errors.New("no eligible bid")
+
+// Selector selects the bid to lease among the open bids of an order.
+type Selector interface {
+	Select(ctx context.Context, bids []mtypes.Bid) (mtypes.Bid, error)
+}
+
+// SelectorFunc adapts a function to Selector.
+type SelectorFunc func(ctx context.Context, bids []mtypes.Bid) (mtypes.Bid, error)
+
+func (f SelectorFunc) Select(ctx context.Context, bids []mtypes.Bid) (mtypes.Bid, error) {
+	return f(ctx, bids)
+}
+
+// Cheapest selects the bid with the lowest price. Bids of the same price are
+// ordered by provider address, for the selection to be stable. It fails when
+// the bids are not all priced in the same denom.
+func Cheapest() Selector {
+	return SelectorFunc(func(_ context.Context, bids []mtypes.Bid) (mtypes.Bid, error) {
+		return cheapest(bids)
+	})
+}
+
+// CheapestFrom selects the cheapest bid among the bids of providers.
+func CheapestFrom(providers ...string) Selector {
+	allowed := make(map[string]bool, len(providers))
+	for _, p := range providers {
+		allowed[p] = true
+	}
+
+	return SelectorFunc(func(_ context.Context, bids []mtypes.Bid) (mtypes.Bid, error) {
+		var eligible []mtypes.Bid
+		for _, bid := range bids {
+			if allowed[bid.BidID.Provider] {
+				eligible = append(eligible, bid)
+			}
+		}
+		return cheapest(eligible)
+	})
+}
+
+// CheapestAudited selects the cheapest bid among the providers whose
+// attributes, as signed by at least one of auditors, include attributes.
+func (s Service) CheapestAudited(attributes v1beta2.Attributes, auditors ...string) Selector {
+	return SelectorFunc(func(ctx context.Context, bids []mtypes.Bid) (mtypes.Bid, error) {
+		var eligible []mtypes.Bid
+		for _, bid := range bids {
+			ok, err := s.audited(ctx, bid.BidID.Provider, attributes, auditors)
+			if err != nil {
+				return mtypes.Bid{}, err
+			}
+			if ok {
+				eligible = append(eligible, bid)
+			}
+		}
+		return cheapest(eligible)
+	})
+}
+
+// ByScore selects the bid with the highest score. Bids with a negative score
+// are not eligible.
+func ByScore(score func(mtypes.Bid) float64) Selector {
+	return SelectorFunc(func(_ context.Context, bids []mtypes.Bid) (mtypes.Bid, error) {
+		best, bestScore := -1, 0.0
+		for i, bid := range bids {
+			if sc := score(bid); sc >= 0 && (best < 0 || sc > bestScore) {
+				best, bestScore = i, sc
+			}
+		}
+		if best < 0 {
+			return mtypes.Bid{}, errors.WithStack(ErrNoEligibleBid)
+		}
+		return bids[best], nil
+	})
+}
+
+func cheapest(bids []mtypes.Bid) (mtypes.Bid, error) {
+	if len(bids) == 0 {
+		return mtypes.Bid{}, errors.WithStack(ErrNoEligibleBid)
+	}
+	// prices in different denoms cannot be compared.
+	for _, bid := range bids[1:] {
+		if bid.Price.Denom != bids[0].Price.Denom {
+			return mtypes.Bid{}, errors.Errorf("bids are priced in both %s and %s", bids[0].Price.Denom, bid.Price.Denom)
+		}
+	}
+
+	sorted := append([]mtypes.Bid{}, bids...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if !a.Price.Amount.Equal(b.Price.Amount) {
+			return a.Price.Amount.LT(b.Price.Amount)
+		}
+		return a.BidID.Provider < b.BidID.Provider
+	})
+	return sorted[0], nil
+}
+
+// audited reports whether attributes are signed for provider by one of
+// auditors.
+func (s Service) audited(ctx context.Context, provider string, attributes v1beta2.Attributes, auditors []string) (bool, error) {
+	for _, auditor := range auditors {
+		resp, err := s.auditQueryClient.ProviderAuditorAttributes(ctx, &atypes.QueryProviderAuditorRequest{
+			Auditor: auditor,
+			Owner:   provider,
+		})
+		// the audit module answers NotFound when the auditor signed nothing.
+		if status.Code(errors.Cause(err)) == codes.NotFound {
+			continue
+		}
+		if err != nil {
+			return false, errors.Wrapf(err, "fetching attributes of %s signed by %s", provider, auditor)
+		}
+
+		for _, p := range resp.Providers {
+			if attributes.SubsetOf(p.Attributes) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,337 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+
+	"akashrpcclient/client"
+	"akashrpcclient/spend"
+
+	"github.com/akash-network/node/x/deployment/types/v1beta2"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+// Rule names reported by ViolationError.
+const (
+	RuleMaxDeploymentDeposit = "max-deployment-deposit"
+	RuleMaxDailySpend        = "max-daily-spend"
+	RuleAllowedRecipients    = "allowed-recipients"
+	RuleAllowedMsgTypes      = "allowed-msg-types"
+)
+
+// Policy declares the spending limits enforced by the Engine.
+// Zero values disable the corresponding rule. The messages executed through
+// authz MsgExec are evaluated as if the signer sent them.
+type Policy struct {
+	// MaxDeploymentDeposit caps the amount deposited into a single deployment
+	// by one tx, either on creation or via MsgDepositDeployment.
+	MaxDeploymentDeposit sdktypes.Coins
+
+	// MaxDailySpend caps the amount an account spends per UTC day, including
+	// deployment deposits, bank sends and tx fees.
+	MaxDailySpend sdktypes.Coins
+
+	// AllowedRecipients lists the bech32 addresses bank sends may go to.
+	AllowedRecipients []string
+
+	// AllowedMsgTypes maps a key name to the msg type URLs it may sign.
+	// Keys missing from the map are not restricted.
+	AllowedMsgTypes map[string][]string
+}
+
+// ViolationError is returned when a tx breaks a rule of the policy.
+type ViolationError struct {
+	Rule    string
+	Account string
+	Reason  string
+}
+
+func (e *ViolationError) Error() string {
+	return fmt.Sprintf("policy %q violated by account %q: %s", e.Rule, e.Account, e.Reason)
+}
+
+// Option configures your engine.
+type Option func(*Engine)
+
+// WithLogger sets the logger violations are reported to, stderr by default.
+func WithLogger(logger *log.Logger) Option {
+	return func(e *Engine) {
+		e.logger = logger
+	}
+}
+
+// WithLedger sets the ledger the daily spending is recorded in, an in-memory
+// ledger by default, which starts over on restart. A ledger opened with
+// spend.OpenLedger keeps MaxDailySpend enforced across restarts.
+func WithLedger(ledger *spend.Ledger) Option {
+	return func(e *Engine) {
+		e.ledger = ledger
+	}
+}
+
+// Engine evaluates a Policy against every tx before it is signed.
+// It implements client.Interceptor and is installed with client.WithInterceptors.
+type Engine struct {
+	client.NopInterceptor
+
+	policy Policy
+	logger *log.Logger
+	ledger *spend.Ledger
+
+	// mu makes the check of the daily spend and its reservation atomic.
+	mu       sync.Mutex
+	reserved map[*client.TxInfo]spend.Reservation
+}
+
+// New creates a new policy engine.
+func New(policy Policy, options ...Option) *Engine {
+	e := &Engine{
+		policy:   policy,
+		logger:   log.New(os.Stderr, "", log.LstdFlags),
+		ledger:   spend.NewLedger(),
+		reserved: make(map[*client.TxInfo]spend.Reservation),
+	}
+
+	for _, apply := range options {
+		apply(e)
+	}
+
+	return e
+}
+
+// BeforeSign vetoes the tx if one of its messages violates the policy.
+func (e *Engine) BeforeSign(_ context.Context, info *client.TxInfo) error {
+	if err := e.evaluate(info); err != nil {
+		e.logger.Println(err)
+		return err
+	}
+	return nil
+}
+
+// AfterBroadcast releases the daily spend reserved for a tx which did not
+// reach the mempool, see client.NotAccepted. The reservation is kept on other
+// errors, since the tx may still be committed.
+func (e *Engine) AfterBroadcast(_ context.Context, info *client.TxInfo, _ *sdktypes.TxResponse, err error) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	r, ok := e.reserved[info]
+	if !ok {
+		return nil
+	}
+	delete(e.reserved, info)
+
+	if client.NotAccepted(err) {
+		return e.ledger.Release(r)
+	}
+	return nil
+}
+
+// evaluate checks info against the policy and reserves its spending in the
+// ledger.
+func (e *Engine) evaluate(info *client.TxInfo) error {
+	violation := func(rule, format string, args ...interface{}) error {
+		return &ViolationError{
+			Rule:    rule,
+			Account: info.AccountName,
+			Reason:  fmt.Sprintf(format, args...),
+		}
+	}
+
+	msgs, executed, err := flatten(info.Msgs)
+	if err != nil {
+		return violation(RuleAllowedMsgTypes, "%s", err)
+	}
+
+	if allowed, ok := e.policy.AllowedMsgTypes[info.AccountName]; ok {
+		for _, msg := range msgs {
+			if typeURL := sdktypes.MsgTypeURL(msg); !contains(allowed, typeURL) {
+				return violation(RuleAllowedMsgTypes, "message type %s is not allowed", typeURL)
+			}
+		}
+	}
+
+	if len(e.policy.AllowedRecipients) != 0 {
+		for _, recipient := range recipients(msgs) {
+			if !contains(e.policy.AllowedRecipients, recipient) {
+				return violation(RuleAllowedRecipients, "recipient %s is not allowed", recipient)
+			}
+		}
+	}
+
+	if !e.policy.MaxDeploymentDeposit.Empty() {
+		for id, deposit := range deposits(info.From, msgs, executed) {
+			if !deposit.IsAllLTE(e.policy.MaxDeploymentDeposit) {
+				return violation(RuleMaxDeploymentDeposit, "deposit %s into deployment %s exceeds %s",
+					deposit, id, e.policy.MaxDeploymentDeposit)
+			}
+		}
+	}
+
+	if !e.policy.MaxDailySpend.Empty() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		key := string(info.From)
+		amount := spending(info, msgs, executed)
+		total := e.ledger.Spent(key).Add(amount...)
+		if !total.IsAllLTE(e.policy.MaxDailySpend) {
+			return violation(RuleMaxDailySpend, "daily spend %s exceeds %s", total, e.policy.MaxDailySpend)
+		}
+
+		// the spending is reserved before the tx is signed so that concurrent
+		// txs see it, AfterBroadcast releases it if the tx is rejected.
+		r, err := e.ledger.Add(key, amount)
+		if err != nil {
+			return err
+		}
+		e.pruneReserved(r.Day())
+		e.reserved[info] = r
+	}
+
+	return nil
+}
+
+// pruneReserved forgets the reservations of the txs which never reached
+// AfterBroadcast, e.g. when the client was not running them, once their day
+// is over. It must be called with e.mu held.
+func (e *Engine) pruneReserved(today string) {
+	for info, r := range e.reserved {
+		if r.Day() != today {
+			delete(e.reserved, info)
+		}
+	}
+}
+
+// flatten returns msgs with the messages of authz MsgExec appended, at any
+// depth, and the set of the executed messages. MsgExec is kept, so that both
+// it and the executed messages must be allowed.
+func flatten(msgs []sdktypes.Msg) ([]sdktypes.Msg, map[sdktypes.Msg]bool, error) {
+	res := make([]sdktypes.Msg, 0, len(msgs))
+	executed := make(map[sdktypes.Msg]bool)
+	for _, msg := range msgs {
+		res = append(res, msg)
+
+		exec, ok := msg.(*authz.MsgExec)
+		if !ok {
+			continue
+		}
+		inner, err := exec.GetMessages()
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot decode the messages executed by MsgExec: %w", err)
+		}
+		inner, innerExecuted, err := flatten(inner)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, msg := range inner {
+			executed[msg] = true
+		}
+		for msg := range innerExecuted {
+			executed[msg] = true
+		}
+		res = append(res, inner...)
+	}
+	return res, executed, nil
+}
+
+// deposits sums the funds moved by signer into each deployment.
+// Deposits paid by a third-party depositor are not counted, unless executed
+// by signer on behalf of the depositor.
+func deposits(signer sdktypes.AccAddress, msgs []sdktypes.Msg, executed map[sdktypes.Msg]bool) map[string]sdktypes.Coins {
+	res := make(map[string]sdktypes.Coins)
+	for _, msg := range msgs {
+		var (
+			id        v1beta2.DeploymentID
+			depositor string
+			amount    sdktypes.Coin
+		)
+		switch msg := msg.(type) {
+		case *v1beta2.MsgCreateDeployment:
+			id, depositor, amount = msg.ID, msg.Depositor, msg.Deposit
+		case *v1beta2.MsgDepositDeployment:
+			id, depositor, amount = msg.ID, msg.Depositor, msg.Amount
+		default:
+			continue
+		}
+		if !executed[msg] && !isSigner(signer, depositor) {
+			continue
+		}
+		res[id.String()] = res[id.String()].Add(amount)
+	}
+	return res
+}
+
+// spending sums the funds info.From spends with the tx of msgs, fees included.
+func spending(info *client.TxInfo, msgs []sdktypes.Msg, executed map[sdktypes.Msg]bool) sdktypes.Coins {
+	total := sdktypes.NewCoins()
+	for _, coins := range deposits(info.From, msgs, executed) {
+		total = total.Add(coins...)
+	}
+
+	for _, msg := range msgs {
+		switch msg := msg.(type) {
+		case *banktypes.MsgSend:
+			if executed[msg] || isSigner(info.From, msg.FromAddress) {
+				total = total.Add(msg.Amount...)
+			}
+		case *banktypes.MsgMultiSend:
+			for _, in := range msg.Inputs {
+				if executed[msg] || isSigner(info.From, in.Address) {
+					total = total.Add(in.Coins...)
+				}
+			}
+		}
+	}
+
+	if info.TxBuilder != nil {
+		total = total.Add(info.TxBuilder.GetTx().GetFee()...)
+	}
+
+	return total
+}
+
+// recipients lists the addresses receiving bank sends.
+func recipients(msgs []sdktypes.Msg) []string {
+	var res []string
+	for _, msg := range msgs {
+		switch msg := msg.(type) {
+		case *banktypes.MsgSend:
+			res = append(res, msg.ToAddress)
+		case *banktypes.MsgMultiSend:
+			for _, out := range msg.Outputs {
+				res = append(res, out.Address)
+			}
+		}
+	}
+	return res
+}
+
+// isSigner reports whether the bech32 address, whatever its prefix, belongs to
+// the signer. An empty address defaults to the signer.
+func isSigner(signer sdktypes.AccAddress, address string) bool {
+	if address == "" {
+		return true
+	}
+	_, bz, err := bech32.DecodeAndConvert(address)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(signer, bz)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,238 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+
+	"akashrpcclient/client"
+
+	"github.com/akash-network/node/x/deployment/types/v1beta2"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+var (
+	signer    = sdktypes.AccAddress("signer______________")
+	granter   = sdktypes.AccAddress("granter_____________")
+	recipient = sdktypes.AccAddress("recipient___________")
+	stranger  = sdktypes.AccAddress("stranger____________")
+)
+
+func uakt(amount int64) sdktypes.Coins {
+	return sdktypes.NewCoins(sdktypes.NewInt64Coin("uakt", amount))
+}
+
+func send(from, to sdktypes.AccAddress, amount int64) sdktypes.Msg {
+	return banktypes.NewMsgSend(from, to, uakt(amount))
+}
+
+func deposit(dseq uint64, depositor sdktypes.AccAddress, amount int64) sdktypes.Msg {
+	return &v1beta2.MsgDepositDeployment{
+		ID:        v1beta2.DeploymentID{Owner: signer.String(), DSeq: dseq},
+		Amount:    sdktypes.NewInt64Coin("uakt", amount),
+		Depositor: depositor.String(),
+	}
+}
+
+func exec(msgs ...sdktypes.Msg) sdktypes.Msg {
+	msg := authz.NewMsgExec(signer, msgs)
+	return &msg
+}
+
+func newEngine(policy Policy) *Engine {
+	return New(policy, WithLogger(log.New(io.Discard, "", 0)))
+}
+
+func TestBeforeSign(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		msgs   []sdktypes.Msg
+
+		// spent is the daily spend recorded before the tx.
+		spent int64
+
+		// rule is the violated rule, if any.
+		rule string
+	}{
+		{
+			name:   "deposit within the limit",
+			policy: Policy{MaxDeploymentDeposit: uakt(100)},
+			msgs:   []sdktypes.Msg{deposit(1, signer, 100)},
+		},
+		{
+			name:   "deposit above the limit",
+			policy: Policy{MaxDeploymentDeposit: uakt(100)},
+			msgs:   []sdktypes.Msg{deposit(1, signer, 101)},
+			rule:   RuleMaxDeploymentDeposit,
+		},
+		{
+			name:   "deposits summed per deployment",
+			policy: Policy{MaxDeploymentDeposit: uakt(100)},
+			msgs:   []sdktypes.Msg{deposit(1, signer, 60), deposit(1, signer, 60)},
+			rule:   RuleMaxDeploymentDeposit,
+		},
+		{
+			name:   "deposits into distinct deployments",
+			policy: Policy{MaxDeploymentDeposit: uakt(100)},
+			msgs:   []sdktypes.Msg{deposit(1, signer, 60), deposit(2, signer, 60)},
+		},
+		{
+			name:   "deposit paid by a third party",
+			policy: Policy{MaxDeploymentDeposit: uakt(100)},
+			msgs:   []sdktypes.Msg{deposit(1, granter, 200)},
+		},
+		{
+			name:   "deposit executed on behalf of a granter",
+			policy: Policy{MaxDeploymentDeposit: uakt(100)},
+			msgs:   []sdktypes.Msg{exec(deposit(1, granter, 200))},
+			rule:   RuleMaxDeploymentDeposit,
+		},
+		{
+			name:   "daily spend within the limit",
+			policy: Policy{MaxDailySpend: uakt(100)},
+			msgs:   []sdktypes.Msg{send(signer, recipient, 40)},
+			spent:  60,
+		},
+		{
+			name:   "daily spend above the limit",
+			policy: Policy{MaxDailySpend: uakt(100)},
+			msgs:   []sdktypes.Msg{send(signer, recipient, 41)},
+			spent:  60,
+			rule:   RuleMaxDailySpend,
+		},
+		{
+			name:   "send executed on behalf of a granter",
+			policy: Policy{MaxDailySpend: uakt(100)},
+			msgs:   []sdktypes.Msg{exec(send(granter, recipient, 101))},
+			rule:   RuleMaxDailySpend,
+		},
+		{
+			name:   "send from another account",
+			policy: Policy{MaxDailySpend: uakt(100)},
+			msgs:   []sdktypes.Msg{send(stranger, recipient, 101)},
+		},
+		{
+			name:   "allowed recipient",
+			policy: Policy{AllowedRecipients: []string{recipient.String()}},
+			msgs:   []sdktypes.Msg{send(signer, recipient, 1)},
+		},
+		{
+			name:   "recipient not allowed",
+			policy: Policy{AllowedRecipients: []string{recipient.String()}},
+			msgs:   []sdktypes.Msg{send(signer, stranger, 1)},
+			rule:   RuleAllowedRecipients,
+		},
+		{
+			name:   "executed recipient not allowed",
+			policy: Policy{AllowedRecipients: []string{recipient.String()}},
+			msgs:   []sdktypes.Msg{exec(exec(send(granter, stranger, 1)))},
+			rule:   RuleAllowedRecipients,
+		},
+		{
+			name:   "allowed msg type",
+			policy: Policy{AllowedMsgTypes: map[string][]string{"key": {sdktypes.MsgTypeURL(&banktypes.MsgSend{})}}},
+			msgs:   []sdktypes.Msg{send(signer, recipient, 1)},
+		},
+		{
+			name:   "msg type not allowed",
+			policy: Policy{AllowedMsgTypes: map[string][]string{"key": {sdktypes.MsgTypeURL(&banktypes.MsgSend{})}}},
+			msgs:   []sdktypes.Msg{deposit(1, signer, 1)},
+			rule:   RuleAllowedMsgTypes,
+		},
+		{
+			name:   "msg types of other keys",
+			policy: Policy{AllowedMsgTypes: map[string][]string{"other": {sdktypes.MsgTypeURL(&banktypes.MsgSend{})}}},
+			msgs:   []sdktypes.Msg{deposit(1, signer, 1)},
+		},
+		{
+			name: "executed msg type not allowed",
+			policy: Policy{AllowedMsgTypes: map[string][]string{"key": {
+				sdktypes.MsgTypeURL(&authz.MsgExec{}),
+				sdktypes.MsgTypeURL(&banktypes.MsgSend{}),
+			}}},
+			msgs: []sdktypes.Msg{exec(deposit(1, granter, 1))},
+			rule: RuleAllowedMsgTypes,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newEngine(tt.policy)
+			if tt.spent != 0 {
+				if _, err := e.ledger.Add(string(signer), uakt(tt.spent)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			info := &client.TxInfo{AccountName: "key", From: signer, Msgs: tt.msgs}
+			err := e.BeforeSign(context.Background(), info)
+
+			var violation *ViolationError
+			switch {
+			case tt.rule == "" && err != nil:
+				t.Errorf("BeforeSign() error = %v, want nil", err)
+			case tt.rule != "" && !errors.As(err, &violation):
+				t.Errorf("BeforeSign() error = %v, want a ViolationError", err)
+			case tt.rule != "" && violation.Rule != tt.rule:
+				t.Errorf("BeforeSign() violated %q, want %q", violation.Rule, tt.rule)
+			}
+		})
+	}
+}
+
+func TestAfterBroadcastRelease(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *sdktypes.TxResponse
+		err  error
+
+		// kept is set when the spending stays reserved.
+		kept bool
+	}{
+		{
+			name: "accepted",
+			resp: &sdktypes.TxResponse{},
+			kept: true,
+		},
+		{
+			name: "not accepted",
+			err:  &client.TxNotAcceptedError{Err: errors.New("rejected")},
+		},
+		{
+			name: "unknown outcome",
+			err:  errors.New("timed out"),
+			kept: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newEngine(Policy{MaxDailySpend: uakt(100)})
+			ctx := context.Background()
+
+			info := &client.TxInfo{From: signer, Msgs: []sdktypes.Msg{send(signer, recipient, 60)}}
+			if err := e.BeforeSign(ctx, info); err != nil {
+				t.Fatal(err)
+			}
+			if err := e.AfterBroadcast(ctx, info, tt.resp, tt.err); err != nil {
+				t.Fatal(err)
+			}
+
+			want := sdktypes.NewCoins()
+			if tt.kept {
+				want = uakt(60)
+			}
+			if got := e.ledger.Spent(string(signer)); !got.IsEqual(want) {
+				t.Errorf("spent = %s, want %s", got, want)
+			}
+			if len(e.reserved) != 0 {
+				t.Errorf("%d reservations are left", len(e.reserved))
+			}
+		})
+	}
+}
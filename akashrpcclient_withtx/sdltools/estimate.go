@@ -0,0 +1,135 @@
+package sdltools
+
+import (
+	"time"
+
+	"akashrpcclient/internal/escrow"
+
+	"github.com/akash-network/node/sdl"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+)
+
+// ServiceResources are the resources requested by one instance of a service.
+type ServiceResources struct {
+	Service string
+
+	// Count is the number of instances of the service.
+	Count uint32
+
+	// CPU is in millicores, Memory and the Storage volumes, by name, are in
+	// bytes.
+	CPU     uint64
+	Memory  uint64
+	Storage map[string]uint64
+}
+
+// GroupEstimate is the cost estimate of a deployment group.
+type GroupEstimate struct {
+	Name string
+
+	// MaxPrice is the maximum price per block the group pays, the sum of the
+	// prices of its services times their count.
+	MaxPrice sdktypes.DecCoins
+
+	Services []ServiceResources
+}
+
+// Estimate is the cost estimate of an SDL.
+type Estimate struct {
+	Groups []GroupEstimate
+
+	// MaxPrice is the maximum price per block of the deployment, the sum of
+	// the maximum prices of its groups.
+	MaxPrice sdktypes.DecCoins
+}
+
+// EstimateCost computes the maximum price per block of the SDL in buf from
+// the pricing of its placements, and the resources of its services.
+func EstimateCost(buf []byte) (Estimate, error) {
+	s, err := sdl.Read(buf)
+	if err != nil {
+		return Estimate{}, errors.Wrap(err, "reading SDL")
+	}
+
+	groups, err := s.DeploymentGroups()
+	if err != nil {
+		return Estimate{}, errors.Wrap(err, "reading SDL deployment groups")
+	}
+
+	m, err := s.Manifest()
+	if err != nil {
+		return Estimate{}, errors.Wrap(err, "reading SDL manifest")
+	}
+
+	var e Estimate
+	for _, group := range groups {
+		g := GroupEstimate{
+			Name: group.Name,
+		}
+		for _, resource := range group.Resources {
+			g.MaxPrice = g.MaxPrice.Add(sdktypes.NewDecCoinFromDec(resource.Price.Denom,
+				resource.Price.Amount.MulInt64(int64(resource.Count))))
+		}
+
+		for _, mgroup := range m {
+			if mgroup.Name != group.Name {
+				continue
+			}
+			for _, svc := range mgroup.Services {
+				r := ServiceResources{
+					Service: svc.Name,
+					Count:   svc.Count,
+					Storage: make(map[string]uint64, len(svc.Resources.Storage)),
+				}
+				if cpu := svc.Resources.CPU; cpu != nil {
+					r.CPU = cpu.Units.Value()
+				}
+				if memory := svc.Resources.Memory; memory != nil {
+					r.Memory = memory.Quantity.Value()
+				}
+				for _, volume := range svc.Resources.Storage {
+					r.Storage[volume.Name] = volume.Quantity.Value()
+				}
+				g.Services = append(g.Services, r)
+			}
+		}
+
+		e.MaxPrice = e.MaxPrice.Add(g.MaxPrice...)
+		e.Groups = append(e.Groups, g)
+	}
+
+	return e, nil
+}
+
+// Deposit returns the deposit paying for period at the maximum prices,
+// rounded up, with blocks produced every blockTime. For a number of days,
+// period is n * 24 * time.Hour. It returns nothing when blockTime is not
+// positive.
+func (e Estimate) Deposit(period, blockTime time.Duration) sdktypes.Coins {
+	if blockTime <= 0 {
+		return nil
+	}
+	blocks := int64((period + blockTime - 1) / blockTime)
+
+	var deposit sdktypes.Coins
+	for _, price := range e.MaxPrice {
+		amount := price.Amount.MulInt64(blocks).Ceil().TruncateInt()
+		deposit = deposit.Add(sdktypes.NewCoin(price.Denom, amount))
+	}
+	return deposit
+}
+
+// Runway returns the time deposit pays for at the maximum prices, with blocks
+// produced every blockTime. It fails when the prices are not in the denom of
+// the deposit, as the escrow account of a deployment is in a single denom.
+func (e Estimate) Runway(deposit sdktypes.Coin, blockTime time.Duration) (time.Duration, error) {
+	for _, price := range e.MaxPrice {
+		if price.Denom != deposit.Denom {
+			return 0, errors.Errorf("price of %s is not in the denom of the deposit %s", price, deposit)
+		}
+	}
+
+	blocks := escrow.Blocks(deposit.Amount.ToDec(), e.MaxPrice.AmountOf(deposit.Denom))
+	return escrow.Duration(blocks, blockTime), nil
+}
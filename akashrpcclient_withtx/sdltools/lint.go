@@ -0,0 +1,190 @@
+package sdltools
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// lintProfiles warns about the compute and placement profiles no service is
+// deployed with.
+func (v *validator) lintProfiles() {
+	computeUsed := make(map[string]bool)
+	placementUsed := make(map[string]bool)
+
+	_, deployment := lookup(v.root, "deployment")
+	for _, svc := range pairs(deployment) {
+		for _, placement := range pairs(svc.value) {
+			placementUsed[placement.key.Value] = true
+			if _, profile := lookup(placement.value, "profile"); profile != nil {
+				computeUsed[profile.Value] = true
+			}
+		}
+	}
+
+	_, compute := lookup(v.root, "profiles", "compute")
+	for _, p := range pairs(compute) {
+		if !computeUsed[p.key.Value] {
+			v.report(p.key, SeverityWarning, RuleUnreferencedProfile,
+				fmt.Sprintf("compute profile %q is not used by any deployment", p.key.Value))
+		}
+	}
+
+	_, placement := lookup(v.root, "profiles", "placement")
+	for _, p := range pairs(placement) {
+		if !placementUsed[p.key.Value] {
+			v.report(p.key, SeverityWarning, RuleUnreferencedProfile,
+				fmt.Sprintf("placement profile %q is not used by any deployment", p.key.Value))
+		}
+	}
+}
+
+// lintIPLeases reports the services exposed to an IP endpoint deployed on a
+// placement which does not require the iplease attribute, as only the
+// providers offering IP leases can bid on them.
+func (v *validator) lintIPLeases() {
+	_, services := lookup(v.root, "services")
+	for _, svc := range pairs(services) {
+		_, expose := lookup(svc.value, "expose")
+		for _, exp := range items(expose) {
+			_, to := lookup(exp, "to")
+			for _, dest := range items(to) {
+				_, ip := lookup(dest, "ip")
+				if ip == nil || ip.Value == "" {
+					continue
+				}
+
+				_, placements := lookup(v.root, "deployment", svc.key.Value)
+				for _, placement := range pairs(placements) {
+					_, attr := lookup(v.root, "profiles", "placement", placement.key.Value, "attributes", "iplease")
+					if attr == nil || attr.Value != "true" {
+						v.report(ip, SeverityError, RuleIPLease,
+							fmt.Sprintf("service %q is exposed to IP endpoint %q but placement %q does not require the iplease attribute",
+								svc.key.Value, ip.Value, placement.key.Value))
+					}
+				}
+			}
+		}
+	}
+}
+
+// lintPricing reports the prices in a denom not accepted for deployments.
+func (v *validator) lintPricing() {
+	for _, r := range v.rejectedDenoms() {
+		v.report(r.denom, SeverityError, RulePricingDenom,
+			fmt.Sprintf("price of %q in placement %q is in %q, expected one of %q",
+				r.price, r.placement, r.denom.Value, v.denoms))
+	}
+}
+
+// rejectedDenom is the denom of a price not accepted for deployments.
+type rejectedDenom struct {
+	placement string
+	price     string
+	denom     *yaml.Node
+}
+
+// rejectedDenoms lists the prices in a denom not accepted for deployments.
+func (v *validator) rejectedDenoms() []rejectedDenom {
+	var res []rejectedDenom
+	_, placements := lookup(v.root, "profiles", "placement")
+	for _, placement := range pairs(placements) {
+		_, pricing := lookup(placement.value, "pricing")
+		for _, price := range pairs(pricing) {
+			_, denom := lookup(price.value, "denom")
+			if denom == nil || denom.Kind != yaml.ScalarNode || contains(v.denoms, denom.Value) {
+				continue
+			}
+			res = append(res, rejectedDenom{placement.key.Value, price.key.Value, denom})
+		}
+	}
+	return res
+}
+
+// lintStorage warns about the persistent storage without a class, which gets
+// the default class whatever the providers offer.
+func (v *validator) lintStorage() {
+	_, compute := lookup(v.root, "profiles", "compute")
+	for _, profile := range pairs(compute) {
+		_, storage := lookup(profile.value, "resources", "storage")
+		if storage == nil {
+			continue
+		}
+
+		volumes := items(storage)
+		if storage.Kind == yaml.MappingNode {
+			volumes = []*yaml.Node{storage}
+		}
+
+		for _, volume := range volumes {
+			_, persistent := lookup(volume, "attributes", "persistent")
+			if persistent == nil || persistent.Value != "true" {
+				continue
+			}
+			if _, class := lookup(volume, "attributes", "class"); class != nil {
+				continue
+			}
+
+			name := "default"
+			if _, n := lookup(volume, "name"); n != nil {
+				name = n.Value
+			}
+			v.report(persistent, SeverityWarning, RuleStorageClass,
+				fmt.Sprintf("persistent storage %q of compute profile %q has no class", name, profile.key.Value))
+		}
+	}
+}
+
+type pair struct {
+	key   *yaml.Node
+	value *yaml.Node
+}
+
+// pairs returns the key and value pairs of a mapping node, none for other
+// nodes.
+func pairs(n *yaml.Node) []pair {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	res := make([]pair, 0, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		res = append(res, pair{n.Content[i], n.Content[i+1]})
+	}
+	return res
+}
+
+// items returns the items of a sequence node, none for other nodes.
+func items(n *yaml.Node) []*yaml.Node {
+	if n == nil || n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return n.Content
+}
+
+// lookup returns the key and value nodes at path in mapping n, nil if any
+// element of the path is missing.
+func lookup(n *yaml.Node, path ...string) (key, value *yaml.Node) {
+	value = n
+	for _, elem := range path {
+		found := false
+		for _, p := range pairs(value) {
+			if p.key.Value == elem {
+				key, value, found = p.key, p.value, true
+				break
+			}
+		}
+		if !found {
+			return nil, nil
+		}
+	}
+	return key, value
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
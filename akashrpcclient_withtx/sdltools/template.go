@@ -0,0 +1,455 @@
+package sdltools
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Variable types of templates.
+const (
+	TypeString  = "string"
+	TypeInt     = "int"
+	TypeUint    = "uint"
+	TypeDecimal = "decimal"
+	TypeBool    = "bool"
+)
+
+// Variable is a variable of a template, declared as ${NAME}, ${NAME:type},
+// ${NAME=default} or ${NAME:type=default}. Variables without a default are
+// required. $${ is rendered as a literal ${.
+//
+// String values are rendered as double-quoted scalars when the variable is the
+// whole value of a key or list item. Elsewhere, string values which would
+// change the structure of the YAML document or the type or value of the
+// scalar they are part of are rejected with an UnsafeValueError.
+type Variable struct {
+	Name string
+
+	// Type is the type values are checked against, TypeString by default.
+	Type string
+
+	// Default is the value used when none is given, if HasDefault is set.
+	Default    string
+	HasDefault bool
+
+	// Line is the line of the first use of the variable.
+	Line int
+}
+
+// Required reports whether a value must be given for the variable.
+func (v Variable) Required() bool {
+	return !v.HasDefault
+}
+
+// TemplateSyntaxError is returned for malformed variables.
+type TemplateSyntaxError struct {
+	Line   int
+	Reason string
+}
+
+func (e *TemplateSyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Reason)
+}
+
+// MissingVariablesError is returned when required variables have no value.
+type MissingVariablesError struct {
+	Names []string
+}
+
+func (e *MissingVariablesError) Error() string {
+	return fmt.Sprintf("missing values for variables %s", strings.Join(e.Names, ", "))
+}
+
+// InvalidValueError is returned when a value does not match the type of its
+// variable.
+type InvalidValueError struct {
+	Name  string
+	Type  string
+	Value string
+}
+
+func (e *InvalidValueError) Error() string {
+	return fmt.Sprintf("value %q of variable %s is not a valid %s", e.Value, e.Name, e.Type)
+}
+
+// UnsafeValueError is returned when a string value cannot be embedded in the
+// YAML document where its variable is used.
+type UnsafeValueError struct {
+	Name  string
+	Value string
+	Line  int
+}
+
+func (e *UnsafeValueError) Error() string {
+	return fmt.Sprintf("value %q of variable %s cannot be used on line %d without changing the YAML document",
+		e.Value, e.Name, e.Line)
+}
+
+// Template is an SDL with variables.
+type Template struct {
+	segments  []segment
+	variables []Variable
+}
+
+// segment is either literal text or a reference to a variable.
+type segment struct {
+	text     []byte
+	variable string
+	line     int
+
+	// whole is set when the variable is the whole value of a key or list
+	// item.
+	whole bool
+}
+
+var (
+	declRegexp = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)(?::([a-z]+))?(?:=(.*))?$`)
+
+	// scalarStartRegexp matches the text of a line before a plain scalar,
+	// the key of a mapping or the dash of a list item.
+	scalarStartRegexp = regexp.MustCompile(`^[ \t]*(?:(?:-[ \t]+)+(?:[A-Za-z0-9_.\-/]+:[ \t]+)?|[A-Za-z0-9_.\-/]+:[ \t]+)$`)
+
+	// scalarEndRegexp matches the text of a line after a plain scalar.
+	scalarEndRegexp = regexp.MustCompile(`^(?:[ \t]*|[ \t]+#.*)$`)
+)
+
+// ParseTemplateFile parses the template at path, see ParseTemplate.
+func ParseTemplateFile(path string) (*Template, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return ParseTemplate(buf)
+}
+
+// ParseTemplate parses the variables of the template in buf. A variable used
+// more than once must be declared with the same type and default each time,
+// or with its name alone after its first use.
+func ParseTemplate(buf []byte) (*Template, error) {
+	t := &Template{}
+	declared := make(map[string]int)
+
+	text := func(b []byte) {
+		t.segments = append(t.segments, segment{text: b})
+	}
+
+	for pos := 0; pos < len(buf); {
+		i := bytes.Index(buf[pos:], []byte("${"))
+		if i < 0 {
+			text(buf[pos:])
+			break
+		}
+		start := pos + i
+		line := bytes.Count(buf[:start], []byte("\n")) + 1
+
+		if start > 0 && buf[start-1] == '$' {
+			// $${ escapes a literal ${.
+			text(buf[pos : start-1])
+			text([]byte("${"))
+			pos = start + 2
+			continue
+		}
+		text(buf[pos:start])
+
+		end := bytes.IndexByte(buf[start:], '}')
+		if end < 0 || bytes.IndexByte(buf[start:start+end], '\n') >= 0 {
+			return nil, &TemplateSyntaxError{line, "unclosed variable"}
+		}
+		decl := string(buf[start+2 : start+end])
+		pos = start + end + 1
+
+		v, err := parseVariable(decl, line)
+		if err != nil {
+			return nil, err
+		}
+
+		if idx, ok := declared[v.Name]; ok {
+			first := t.variables[idx]
+			if decl != v.Name && (v.Type != first.Type || v.HasDefault != first.HasDefault || v.Default != first.Default) {
+				return nil, &TemplateSyntaxError{line, fmt.Sprintf("variable %s redeclared differently than on line %d", v.Name, first.Line)}
+			}
+		} else {
+			declared[v.Name] = len(t.variables)
+			t.variables = append(t.variables, v)
+		}
+
+		lineStart := bytes.LastIndexByte(buf[:start], '\n') + 1
+		lineEnd := bytes.IndexByte(buf[pos:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(buf) - pos
+		}
+		t.segments = append(t.segments, segment{
+			variable: v.Name,
+			line:     line,
+			whole: scalarStartRegexp.Match(buf[lineStart:start]) &&
+				scalarEndRegexp.Match(buf[pos:pos+lineEnd]),
+		})
+	}
+
+	return t, nil
+}
+
+func parseVariable(decl string, line int) (Variable, error) {
+	m := declRegexp.FindStringSubmatch(decl)
+	if m == nil {
+		return Variable{}, &TemplateSyntaxError{line, fmt.Sprintf("invalid variable %q", decl)}
+	}
+
+	v := Variable{
+		Name:       m[1],
+		Type:       m[2],
+		Default:    m[3],
+		HasDefault: strings.Contains(decl, "="),
+		Line:       line,
+	}
+	if v.Type == "" {
+		v.Type = TypeString
+	}
+
+	switch v.Type {
+	case TypeString, TypeInt, TypeUint, TypeDecimal, TypeBool:
+	default:
+		return Variable{}, &TemplateSyntaxError{line, fmt.Sprintf("unknown type %q of variable %s", v.Type, v.Name)}
+	}
+
+	if v.HasDefault {
+		if err := checkValue(v, v.Default); err != nil {
+			return Variable{}, &TemplateSyntaxError{line, err.Error()}
+		}
+	}
+
+	return v, nil
+}
+
+// Variables returns the variables of the template, in order of first use.
+func (t *Template) Variables() []Variable {
+	return append([]Variable{}, t.variables...)
+}
+
+// Render renders the template with values, by variable name. Variables
+// missing from values take their default. Values of unknown variables are
+// ignored.
+func (t *Template) Render(values map[string]string) ([]byte, error) {
+	resolved := make(map[string]string, len(t.variables))
+	var missing []string
+	for _, v := range t.variables {
+		value, ok := values[v.Name]
+		switch {
+		case ok:
+			if err := checkValue(v, value); err != nil {
+				return nil, err
+			}
+		case v.HasDefault:
+			value = v.Default
+		default:
+			missing = append(missing, v.Name)
+			continue
+		}
+		resolved[v.Name] = value
+	}
+	if len(missing) != 0 {
+		sort.Strings(missing)
+		return nil, &MissingVariablesError{missing}
+	}
+
+	out, err := t.render(resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	// the template is rendered again with a unique placeholder for each
+	// string value, and both documents must only differ by the values of
+	// the scalars the placeholders are part of.
+	placeholders := make(map[string]string, len(t.variables))
+	var replacements []string
+	for i, v := range t.variables {
+		if v.Type != TypeString {
+			placeholders[v.Name] = resolved[v.Name]
+			continue
+		}
+		placeholders[v.Name] = t.placeholder(i)
+		replacements = append(replacements, placeholders[v.Name], resolved[v.Name])
+	}
+	if len(replacements) == 0 {
+		return out, nil
+	}
+
+	reference, err := t.render(placeholders)
+	if err != nil {
+		return nil, err
+	}
+	want, err := parseDocuments(reference)
+	if err != nil {
+		return nil, errors.Wrap(err, "template is not a valid YAML document")
+	}
+	if got, err := parseDocuments(out); err == nil && sameDocuments(want, got, strings.NewReplacer(replacements...)) {
+		return out, nil
+	}
+
+	// report the first variable whose value alone changes the document.
+	for _, v := range t.variables {
+		if v.Type != TypeString {
+			continue
+		}
+		values := make(map[string]string, len(placeholders))
+		for name, value := range placeholders {
+			values[name] = value
+		}
+		values[v.Name] = resolved[v.Name]
+
+		single, err := t.render(values)
+		if err != nil {
+			return nil, err
+		}
+		got, err := parseDocuments(single)
+		if err != nil || !sameDocuments(want, got, strings.NewReplacer(placeholders[v.Name], resolved[v.Name])) {
+			return nil, &UnsafeValueError{v.Name, resolved[v.Name], v.Line}
+		}
+	}
+	for _, v := range t.variables {
+		if v.Type == TypeString {
+			return nil, &UnsafeValueError{v.Name, resolved[v.Name], v.Line}
+		}
+	}
+	return out, nil
+}
+
+// render renders the template with values, quoting the string values which
+// are the whole value of a key or list item.
+func (t *Template) render(values map[string]string) ([]byte, error) {
+	types := make(map[string]string, len(t.variables))
+	for _, v := range t.variables {
+		types[v.Name] = v.Type
+	}
+
+	var out bytes.Buffer
+	for _, s := range t.segments {
+		if s.variable == "" {
+			out.Write(s.text)
+			continue
+		}
+
+		value := values[s.variable]
+		if types[s.variable] != TypeString || !s.whole {
+			out.WriteString(value)
+			continue
+		}
+		quoted, err := yaml.Marshal(&yaml.Node{
+			Kind:  yaml.ScalarNode,
+			Style: yaml.DoubleQuotedStyle,
+			Value: value,
+		})
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		out.Write(bytes.TrimSuffix(quoted, []byte("\n")))
+	}
+
+	return out.Bytes(), nil
+}
+
+// placeholder returns the placeholder of the i-th variable, a plain scalar
+// which does not occur in the template text.
+func (t *Template) placeholder(i int) string {
+	suffix := "x"
+	for {
+		p := fmt.Sprintf("tplvar%d%s", i, suffix)
+		found := false
+		for _, s := range t.segments {
+			if bytes.Contains(s.text, []byte(p)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return p
+		}
+		suffix += "x"
+	}
+}
+
+// parseDocuments parses all the YAML documents of buf.
+func parseDocuments(buf []byte) ([]*yaml.Node, error) {
+	var docs []*yaml.Node
+	dec := yaml.NewDecoder(bytes.NewReader(buf))
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		docs = append(docs, &doc)
+	}
+}
+
+func sameDocuments(want, got []*yaml.Node, r *strings.Replacer) bool {
+	if len(want) != len(got) {
+		return false
+	}
+	for i := range want {
+		if !sameNode(want[i], got[i], r) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameNode reports whether got has the structure of want, and whether its
+// scalars have the types of those of want and their values once replaced
+// by r.
+func sameNode(want, got *yaml.Node, r *strings.Replacer) bool {
+	if want.Kind != got.Kind || want.Anchor != got.Anchor || len(want.Content) != len(got.Content) {
+		return false
+	}
+	if want.Kind == yaml.ScalarNode || want.Kind == yaml.AliasNode {
+		if want.ShortTag() != got.ShortTag() || r.Replace(want.Value) != got.Value {
+			return false
+		}
+	}
+	for i := range want.Content {
+		if !sameNode(want.Content[i], got.Content[i], r) {
+			return false
+		}
+	}
+	return true
+}
+
+// RenderFile parses and renders the template at path.
+func RenderFile(path string, values map[string]string) ([]byte, error) {
+	t, err := ParseTemplateFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return t.Render(values)
+}
+
+func checkValue(v Variable, value string) error {
+	var err error
+	switch v.Type {
+	case TypeInt:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case TypeUint:
+		_, err = strconv.ParseUint(value, 10, 64)
+	case TypeDecimal:
+		_, err = sdktypes.NewDecFromStr(value)
+	case TypeBool:
+		_, err = strconv.ParseBool(value)
+	}
+	if err != nil {
+		return &InvalidValueError{v.Name, v.Type, value}
+	}
+	return nil
+}
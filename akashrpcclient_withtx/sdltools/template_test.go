@@ -0,0 +1,193 @@
+package sdltools
+
+import (
+	"errors"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const injectionTemplate = `services:
+  web:
+    image: ${IMAGE}
+    env:
+      - ${ENV}
+      - "PASSWORD=${PASSWORD}"
+    command: ["run", "--tag=${TAG}"]
+    args:
+      - --name=${NAME}
+`
+
+func TestRenderInjection(t *testing.T) {
+	safe := map[string]string{
+		"IMAGE":    "nginx:1.25",
+		"ENV":      "DEBUG=1",
+		"PASSWORD": "secret",
+		"TAG":      "v1",
+		"NAME":     "web",
+	}
+
+	tests := []struct {
+		name     string
+		variable string
+		value    string
+
+		// unsafe is set when the value cannot be quoted where it is used.
+		unsafe bool
+	}{
+		{"plain", "IMAGE", "nginx", false},
+		{"newline", "IMAGE", "nginx\nmalicious: true", false},
+		{"colon", "IMAGE", "nginx\ncommand: rm", false},
+		{"key", "ENV", "A: b", false},
+		{"comment", "IMAGE", "nginx #latest", false},
+		{"indicator", "IMAGE", "*alias", false},
+		{"quote", "IMAGE", `"nginx`, false},
+		{"control", "IMAGE", "nginx\x00", false},
+		{"newline in quoted scalar", "PASSWORD", "a\"\nmalicious: true", true},
+		{"quote in flow sequence", "TAG", `v1", "--privileged`, true},
+		{"colon in scalar", "NAME", "web: x", true},
+		{"comment in scalar", "NAME", "web #x", true},
+	}
+
+	tmpl, err := ParseTemplate([]byte(injectionTemplate))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := make(map[string]string, len(safe))
+			for name, value := range safe {
+				values[name] = value
+			}
+			values[tt.variable] = tt.value
+
+			out, err := tmpl.Render(values)
+			if tt.unsafe {
+				var unsafe *UnsafeValueError
+				if !errors.As(err, &unsafe) {
+					t.Fatalf("Render() error = %v, want an UnsafeValueError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			var doc struct {
+				Services map[string]struct {
+					Image   string   `yaml:"image"`
+					Env     []string `yaml:"env"`
+					Command []string `yaml:"command"`
+					Args    []string `yaml:"args"`
+				} `yaml:"services"`
+			}
+			if err := yaml.Unmarshal(out, &doc); err != nil {
+				t.Fatalf("rendered document is invalid: %v\n%s", err, out)
+			}
+			if len(doc.Services) != 1 {
+				t.Fatalf("rendered document has %d services, want 1\n%s", len(doc.Services), out)
+			}
+
+			web := doc.Services["web"]
+			got := map[string]string{
+				"IMAGE": web.Image,
+				"ENV":   web.Env[0],
+			}
+			if want := values[tt.variable]; got[tt.variable] != want {
+				t.Errorf("%s rendered as %q, want %q\n%s", tt.variable, got[tt.variable], want, out)
+			}
+			if len(web.Env) != 2 || len(web.Command) != 2 || len(web.Args) != 1 {
+				t.Errorf("rendered document changed structure\n%s", out)
+			}
+		})
+	}
+}
+
+func TestRenderFlowAndTypedValues(t *testing.T) {
+	tmpl, err := ParseTemplate([]byte("image: ${IMAGE}\ncommand: [${CMD}]\nargs: {name: ${NAME}}\ncount: 1${EXP}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	safe := map[string]string{"IMAGE": "nginx", "CMD": "run", "NAME": "web", "EXP": "a"}
+
+	tests := []struct {
+		name     string
+		variable string
+		value    string
+
+		// unsafe is set when the value cannot be quoted where it is used.
+		unsafe bool
+	}{
+		{"null", "IMAGE", "null", false},
+		{"bool", "IMAGE", "true", false},
+		{"tilde", "IMAGE", "~", false},
+		{"hex", "IMAGE", "0x10", false},
+		{"exponent", "IMAGE", "1e3", false},
+		{"flow sequence", "IMAGE", "[a, b]", false},
+		{"flow sequence items", "CMD", "sh, -c, rm -rf /", true},
+		{"flow sequence end", "CMD", "run], privileged: [true", true},
+		{"flow mapping items", "NAME", "web, privileged: true", true},
+		{"flow mapping end", "NAME", "web}", true},
+		{"null in flow sequence", "CMD", "null", true},
+		{"bool in flow mapping", "NAME", "false", true},
+		{"typed suffix", "EXP", "e3", true},
+		{"plain suffix", "EXP", "0a", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := make(map[string]string, len(safe))
+			for name, value := range safe {
+				values[name] = value
+			}
+			values[tt.variable] = tt.value
+
+			out, err := tmpl.Render(values)
+			if tt.unsafe {
+				var unsafe *UnsafeValueError
+				if !errors.As(err, &unsafe) || unsafe.Name != tt.variable {
+					t.Fatalf("Render() error = %v, want an UnsafeValueError for %s", err, tt.variable)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			var doc struct {
+				Image   interface{}       `yaml:"image"`
+				Command []interface{}     `yaml:"command"`
+				Args    map[string]string `yaml:"args"`
+				Count   interface{}       `yaml:"count"`
+			}
+			if err := yaml.Unmarshal(out, &doc); err != nil {
+				t.Fatalf("rendered document is invalid: %v\n%s", err, out)
+			}
+			if doc.Image != values["IMAGE"] {
+				t.Errorf("image rendered as %#v, want %q\n%s", doc.Image, values["IMAGE"], out)
+			}
+			if len(doc.Command) != 1 || doc.Command[0] != values["CMD"] || len(doc.Args) != 1 {
+				t.Errorf("rendered document changed structure\n%s", out)
+			}
+			if tt.variable == "EXP" && doc.Count != "1"+tt.value {
+				t.Errorf("count rendered as %#v, want %q\n%s", doc.Count, "1"+tt.value, out)
+			}
+		})
+	}
+}
+
+func TestRenderKeepsTypes(t *testing.T) {
+	tmpl, err := ParseTemplate([]byte("port: ${PORT:int}\ncount: ${COUNT:uint}\nname: ${NAME}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := tmpl.Render(map[string]string{"PORT": "80", "COUNT": "2", "NAME": "80"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "port: 80\ncount: 2\nname: \"80\"\n"; string(out) != want {
+		t.Errorf("Render() = %q, want %q", out, want)
+	}
+}
@@ -0,0 +1,251 @@
+// Package sdltools validates and lints Akash SDL files ahead of deployment.
+package sdltools
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/akash-network/node/sdl"
+	"github.com/akash-network/node/x/deployment/types/v1beta2"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Severity is the severity of a diagnostic.
+type Severity string
+
+const (
+	// SeverityError is reported for SDLs the chain or the providers reject.
+	SeverityError Severity = "error"
+
+	// SeverityWarning is reported for SDLs which are valid but likely wrong.
+	SeverityWarning Severity = "warning"
+)
+
+// Rule names reported by Diagnostic.
+const (
+	RuleSyntax              = "syntax"
+	RuleInvalid             = "invalid"
+	RuleUnreferencedProfile = "unreferenced-profile"
+	RuleIPLease             = "ip-lease"
+	RulePricingDenom        = "pricing-denom"
+	RuleStorageClass        = "storage-class"
+)
+
+// Diagnostic is a problem found in an SDL.
+type Diagnostic struct {
+	// Line and Column locate the problem, they are 0 when unknown.
+	Line   int
+	Column int
+
+	Severity Severity
+	Rule     string
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s (%s)", d.Line, d.Column, d.Severity, d.Message, d.Rule)
+}
+
+// Option configures the validation.
+type Option func(*validator)
+
+// WithDenoms sets the denoms pricing may use, uakt by default.
+func WithDenoms(denoms ...string) Option {
+	return func(v *validator) {
+		v.denoms = denoms
+	}
+}
+
+type validator struct {
+	denoms []string
+
+	root        *yaml.Node
+	diagnostics []Diagnostic
+}
+
+// ValidateFile validates the SDL at path, see Validate.
+func ValidateFile(path string, options ...Option) ([]Diagnostic, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return Validate(buf, options...), nil
+}
+
+// Validate parses the SDL in buf as sdl.Read does, validates the deployment
+// it generates as MsgCreateDeployment.ValidateBasic does and lints it for
+// common mistakes. The diagnostics are sorted by line.
+func Validate(buf []byte, options ...Option) []Diagnostic {
+	v := &validator{
+		denoms: []string{"uakt"},
+	}
+	for _, apply := range options {
+		apply(v)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(buf, &doc); err != nil {
+		v.reportErr(RuleSyntax, err)
+		return v.sorted()
+	}
+	if len(doc.Content) == 0 {
+		v.report(nil, SeverityError, RuleSyntax, "empty SDL")
+		return v.sorted()
+	}
+	v.root = doc.Content[0]
+
+	v.validate(v.acceptedDenoms(buf))
+	v.lintProfiles()
+	v.lintIPLeases()
+	v.lintPricing()
+	v.lintStorage()
+
+	return v.sorted()
+}
+
+// HasErrors reports whether diagnostics contain an error.
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// validate reports the errors of the SDL and of the deployment it generates.
+func (v *validator) validate(buf []byte) {
+	s, err := sdl.Read(buf)
+	if err != nil {
+		v.reportErr(RuleInvalid, err)
+		return
+	}
+
+	version, err := sdl.Version(s)
+	if err != nil {
+		v.reportErr(RuleInvalid, err)
+		return
+	}
+
+	groups, err := s.DeploymentGroups()
+	if err != nil {
+		v.reportErr(RuleInvalid, err)
+		return
+	}
+
+	msg := &v1beta2.MsgCreateDeployment{
+		ID: v1beta2.DeploymentID{
+			// any valid owner does, the SDL does not depend on it.
+			Owner: sdktypes.AccAddress(make([]byte, 20)).String(),
+			DSeq:  1,
+		},
+		Version: version,
+		Groups:  make([]v1beta2.GroupSpec, 0, len(groups)),
+	}
+	msg.Depositor = msg.ID.Owner
+	for _, group := range groups {
+		msg.Groups = append(msg.Groups, *group)
+	}
+
+	// the groups are validated one by one to locate and report all of them,
+	// the message validation stops at the first invalid group.
+	valid := true
+	for _, group := range groups {
+		if err := group.ValidateBasic(); err != nil {
+			key, _ := lookup(v.root, "profiles", "placement", group.Name)
+			v.report(key, SeverityError, RuleInvalid, fmt.Sprintf("group %q: %s", group.Name, err))
+			valid = false
+		}
+	}
+
+	if valid {
+		if err := msg.ValidateBasic(); err != nil {
+			v.reportErr(RuleInvalid, err)
+		}
+	}
+}
+
+// acceptedDenoms returns buf with the prices in a denom not accepted, which
+// lintPricing reports, moved to the denom of the chain, so that validate
+// reports the other errors of the SDL only. Lines are left in place.
+func (v *validator) acceptedDenoms(buf []byte) []byte {
+	rejected := v.rejectedDenoms()
+	if len(rejected) == 0 {
+		return buf
+	}
+
+	lines := bytes.SplitAfter(buf, []byte("\n"))
+	for _, r := range rejected {
+		if r.denom.Line < 1 || r.denom.Line > len(lines) {
+			continue
+		}
+		line := []rune(string(lines[r.denom.Line-1]))
+		start := r.denom.Column - 1
+		if start < 0 || start >= len(line) {
+			continue
+		}
+
+		end := start + len([]rune(r.denom.Value))
+		if r.denom.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 {
+			end = -1
+			for i := start + 1; i < len(line); i++ {
+				if line[i] == line[start] {
+					end = i + 1
+					break
+				}
+			}
+		}
+		if end < 0 || end > len(line) {
+			continue
+		}
+		lines[r.denom.Line-1] = []byte(string(line[:start]) + "uakt" + string(line[end:]))
+	}
+	return bytes.Join(lines, nil)
+}
+
+var lineRegexp = regexp.MustCompile(`line (\d+)`)
+
+// reportErr reports an error of the YAML or SDL packages, located by the
+// line it mentions if any.
+func (v *validator) reportErr(rule string, err error) {
+	d := Diagnostic{
+		Severity: SeverityError,
+		Rule:     rule,
+		Message:  err.Error(),
+	}
+	if m := lineRegexp.FindStringSubmatch(err.Error()); m != nil {
+		d.Line, _ = strconv.Atoi(m[1])
+	}
+	v.diagnostics = append(v.diagnostics, d)
+}
+
+// report reports a problem located at node, unknown when node is nil.
+func (v *validator) report(node *yaml.Node, severity Severity, rule, message string) {
+	d := Diagnostic{
+		Severity: severity,
+		Rule:     rule,
+		Message:  message,
+	}
+	if node != nil {
+		d.Line = node.Line
+		d.Column = node.Column
+	}
+	v.diagnostics = append(v.diagnostics, d)
+}
+
+func (v *validator) sorted() []Diagnostic {
+	sort.SliceStable(v.diagnostics, func(i, j int) bool {
+		a, b := v.diagnostics[i], v.diagnostics[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return v.diagnostics
+}
@@ -0,0 +1,68 @@
+package sdltools
+
+import (
+	"strings"
+	"testing"
+)
+
+const denomSDL = `---
+version: "2.0"
+services:
+  web:
+    image: nginx
+    expose:
+      - port: 80
+        to:
+          - global: true
+profiles:
+  compute:
+    web:
+      resources:
+        cpu:
+          units: 0.5
+        memory:
+          size: 512Mi
+        storage:
+          size: 512Mi
+  placement:
+    dc:
+      pricing:
+        web:
+          denom: DENOM
+          amount: AMOUNT
+deployment:
+  web:
+    dc:
+      profile: web
+      count: 1
+`
+
+func TestValidatePricingDenomReportedOnce(t *testing.T) {
+	tests := []struct {
+		name   string
+		denom  string
+		amount string
+		want   []string
+	}{
+		{"accepted", "uakt", "100", nil},
+		{"plain", "uusd", "100", []string{RulePricingDenom}},
+		{"quoted", `"uusd"`, "100", []string{RulePricingDenom}},
+		{"with other errors", "uusd", "0", []string{RuleInvalid, RulePricingDenom}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sdl := strings.NewReplacer("DENOM", tt.denom, "AMOUNT", tt.amount).Replace(denomSDL)
+
+			var rules []string
+			for _, d := range Validate([]byte(sdl)) {
+				if d.Severity == SeverityError {
+					rules = append(rules, d.Rule)
+				}
+			}
+			if strings.Join(rules, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Validate() reported %q, want %q", rules, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+// Package spend records the amounts spent per UTC day, to enforce daily
+// limits across txs and, with a file, across restarts.
+package spend
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+)
+
+// Ledger records the amounts spent today by key. The amounts of the previous
+// days are dropped.
+type Ledger struct {
+	path string
+	now  func() time.Time
+
+	mu    sync.Mutex
+	day   string
+	spent map[string]sdktypes.Coins
+}
+
+// ledgerFile is the JSON content of the file of a ledger.
+type ledgerFile struct {
+	Day   string                    `json:"day"`
+	Spent map[string]sdktypes.Coins `json:"spent"`
+}
+
+// Reservation is an amount recorded by Add, which can be released if it was
+// not spent after all.
+type Reservation struct {
+	key    string
+	day    string
+	amount sdktypes.Coins
+}
+
+// Day returns the UTC day the amount was recorded on, as YYYY-MM-DD.
+func (r Reservation) Day() string {
+	return r.day
+}
+
+// NewLedger returns a ledger kept in memory, which starts empty on each
+// restart.
+func NewLedger() *Ledger {
+	return &Ledger{
+		now:   time.Now,
+		spent: make(map[string]sdktypes.Coins),
+	}
+}
+
+// OpenLedger returns a ledger saved to the JSON file at path on every change,
+// and loaded from it if it exists.
+func OpenLedger(path string) (*Ledger, error) {
+	l := NewLedger()
+	l.path = path
+
+	bz, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading ledger %q", path)
+	}
+
+	var f ledgerFile
+	if err := json.Unmarshal(bz, &f); err != nil {
+		return nil, errors.Wrapf(err, "decoding ledger %q", path)
+	}
+	l.day = f.Day
+	if f.Spent != nil {
+		l.spent = f.Spent
+	}
+
+	return l, nil
+}
+
+// Spent returns the amount recorded today under key.
+func (l *Ledger) Spent(key string) sdktypes.Coins {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rollover()
+	return sdktypes.NewCoins(l.spent[key]...)
+}
+
+// Add records amount as spent today under key.
+func (l *Ledger) Add(key string, amount sdktypes.Coins) (Reservation, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rollover()
+	l.spent[key] = l.spent[key].Add(amount...)
+
+	return Reservation{key, l.day, amount}, l.save()
+}
+
+// Release removes the amount of r from the amounts spent, if it was recorded
+// today.
+func (l *Ledger) Release(r Reservation) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rollover()
+	if r.day != l.day {
+		return nil
+	}
+
+	spent := l.spent[r.key]
+	left := sdktypes.NewCoins()
+	for _, coin := range spent {
+		amount := coin.Amount.Sub(r.amount.AmountOf(coin.Denom))
+		if amount.IsPositive() {
+			left = left.Add(sdktypes.NewCoin(coin.Denom, amount))
+		}
+	}
+	l.spent[r.key] = left
+
+	return l.save()
+}
+
+// rollover drops the amounts of the previous days. It must be called with
+// l.mu held.
+func (l *Ledger) rollover() {
+	day := l.now().UTC().Format("2006-01-02")
+	if l.day != day {
+		l.day = day
+		l.spent = make(map[string]sdktypes.Coins)
+	}
+}
+
+// save writes the ledger to its file, if any, through a temporary file so
+// that a crash never leaves it truncated. It must be called with l.mu held.
+func (l *Ledger) save() error {
+	if l.path == "" {
+		return nil
+	}
+
+	bz, err := json.Marshal(ledgerFile{l.day, l.spent})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), filepath.Base(l.path)+".*")
+	if err != nil {
+		return errors.Wrapf(err, "saving ledger %q", l.path)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(bz); err != nil {
+		tmp.Close()
+		return errors.Wrapf(err, "saving ledger %q", l.path)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return errors.Wrapf(err, "saving ledger %q", l.path)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrapf(err, "saving ledger %q", l.path)
+	}
+
+	return errors.Wrapf(os.Rename(tmp.Name(), l.path), "saving ledger %q", l.path)
+}
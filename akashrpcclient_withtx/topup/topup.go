@@ -0,0 +1,340 @@
+// Package topup keeps the escrow accounts of deployments funded by
+// depositing into them when their runway gets low.
+package topup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"akashrpcclient/account"
+	"akashrpcclient/client"
+	"akashrpcclient/deployment"
+	"akashrpcclient/spend"
+
+	"github.com/akash-network/node/x/deployment/types/v1beta2"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultInterval  = 10 * time.Minute
+	defaultThreshold = 24 * time.Hour
+
+	// ledgerKey is the key the deposits are recorded under in the ledger.
+	ledgerKey = "topup"
+)
+
+// Option configures your daemon.
+type Option func(*Daemon)
+
+// WithDeployments sets the deployments watched by the daemon.
+func WithDeployments(ids ...v1beta2.DeploymentID) Option {
+	return func(d *Daemon) {
+		d.ids = append(d.ids, ids...)
+	}
+}
+
+// WithOwner makes the daemon watch every active deployment of owner, listed
+// again at each check.
+func WithOwner(owner string) Option {
+	return func(d *Daemon) {
+		d.owner = owner
+	}
+}
+
+// WithInterval sets the time between two checks, 10 minutes by default.
+func WithInterval(interval time.Duration) Option {
+	return func(d *Daemon) {
+		d.interval = interval
+	}
+}
+
+// WithThreshold sets the runway under which a deployment is topped up, 24
+// hours by default.
+func WithThreshold(threshold time.Duration) Option {
+	return func(d *Daemon) {
+		d.threshold = threshold
+	}
+}
+
+// WithTarget sets the runway a deployment is topped up to, twice the
+// threshold by default.
+func WithTarget(target time.Duration) Option {
+	return func(d *Daemon) {
+		d.target = target
+	}
+}
+
+// WithDailyBudget caps the amount deposited per UTC day. Deposits are reduced
+// to the budget left and skipped once it is spent. No cap by default.
+//
+// A deposit counts against the budget as soon as it is attempted, and stops
+// counting if it fails before being broadcast or is rejected by the node,
+// see client.NotAccepted. Deposits failing otherwise keep counting, since
+// they may still land, as after a timeout waiting for their inclusion. The
+// amounts are recorded in memory by default, so the budget starts over on
+// restart; see WithLedger to keep them.
+func WithDailyBudget(budget sdktypes.Coins) Option {
+	return func(d *Daemon) {
+		d.budget = budget
+	}
+}
+
+// WithLedger sets the ledger the deposits are recorded in, an in-memory
+// ledger by default. A ledger opened with spend.OpenLedger keeps the budget
+// spent across restarts.
+func WithLedger(ledger *spend.Ledger) Option {
+	return func(d *Daemon) {
+		d.ledger = ledger
+	}
+}
+
+// WithDepositor sets the depositor of the deposits, the owner by default,
+// see deployment.Service.Deposit.
+func WithDepositor(depositor string) Option {
+	return func(d *Daemon) {
+		d.depositor = depositor
+	}
+}
+
+// WithLogger sets the logger top-ups and failures are reported to.
+func WithLogger(logger *log.Logger) Option {
+	return func(d *Daemon) {
+		d.logger = logger
+	}
+}
+
+// TopUp is the outcome of the check of a deployment.
+type TopUp struct {
+	ID v1beta2.DeploymentID
+
+	// Runway is the runway before the deposit.
+	Runway deployment.Runway
+
+	// Amount is the amount deposited, zero when no deposit was made.
+	Amount sdktypes.Coin
+
+	// Skipped is the reason the deposit was not made, if any.
+	Skipped string
+
+	// Err is the error which prevented the check or the deposit.
+	Err error
+}
+
+// Daemon periodically computes the runway of the watched deployments and
+// deposits into the ones running low.
+type Daemon struct {
+	service deployment.Service
+	account account.Account
+
+	ids       []v1beta2.DeploymentID
+	owner     string
+	interval  time.Duration
+	threshold time.Duration
+	target    time.Duration
+	budget    sdktypes.Coins
+	depositor string
+	logger    *log.Logger
+	ledger    *spend.Ledger
+
+	// mu makes the check of the budget left and the record of a deposit
+	// atomic.
+	mu sync.Mutex
+}
+
+// New creates a new daemon depositing from account, the owner of the watched
+// deployments.
+func New(service deployment.Service, account account.Account, options ...Option) (*Daemon, error) {
+	d := &Daemon{
+		service:   service,
+		account:   account,
+		interval:  defaultInterval,
+		threshold: defaultThreshold,
+		logger:    log.New(io.Discard, "", 0),
+		ledger:    spend.NewLedger(),
+	}
+
+	for _, apply := range options {
+		apply(d)
+	}
+
+	if d.target == 0 {
+		d.target = 2 * d.threshold
+	}
+
+	switch {
+	case len(d.ids) == 0 && d.owner == "":
+		return nil, errors.New("no deployment to watch, set the deployments or their owner")
+	case d.interval <= 0:
+		return nil, errors.Errorf("invalid interval %s", d.interval)
+	case d.threshold <= 0:
+		return nil, errors.Errorf("invalid threshold %s", d.threshold)
+	case d.target < d.threshold:
+		return nil, errors.Errorf("target %s is lower than threshold %s", d.target, d.threshold)
+	}
+
+	return d, nil
+}
+
+// Run checks the watched deployments every interval until ctx is done.
+func (d *Daemon) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		for _, t := range d.Check(ctx) {
+			d.report(t)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check checks the watched deployments once, depositing into the ones whose
+// runway is below the threshold.
+func (d *Daemon) Check(ctx context.Context) []TopUp {
+	ids, err := d.watched(ctx)
+	if err != nil {
+		return []TopUp{{Err: err}}
+	}
+
+	topUps := make([]TopUp, 0, len(ids))
+	for _, id := range ids {
+		topUps = append(topUps, d.check(ctx, id))
+	}
+
+	return topUps
+}
+
+func (d *Daemon) watched(ctx context.Context) ([]v1beta2.DeploymentID, error) {
+	if d.owner == "" {
+		return d.ids, nil
+	}
+
+	deployments, err := d.service.ListAll(ctx,
+		deployment.WithOwner(d.owner),
+		deployment.WithState(v1beta2.DeploymentActive),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := append([]v1beta2.DeploymentID{}, d.ids...)
+	for _, dep := range deployments {
+		if !containsID(ids, dep.Deployment.DeploymentID) {
+			ids = append(ids, dep.Deployment.DeploymentID)
+		}
+	}
+
+	return ids, nil
+}
+
+func (d *Daemon) check(ctx context.Context, id v1beta2.DeploymentID) TopUp {
+	t := TopUp{ID: id}
+
+	t.Runway, t.Err = d.service.Runway(ctx, id)
+	if t.Err != nil || !t.Runway.Below(d.threshold) {
+		return t
+	}
+
+	amount := d.amount(t.Runway)
+	if !amount.IsPositive() {
+		t.Skipped = "nothing to deposit"
+		return t
+	}
+
+	var reservation spend.Reservation
+	if amount, reservation, t.Skipped, t.Err = d.reserve(amount); t.Skipped != "" || t.Err != nil {
+		return t
+	}
+
+	if _, t.Err = d.service.Deposit(ctx, d.account, id, amount, d.depositor); t.Err != nil {
+		// the deposit may land unless it is known not to be accepted.
+		if client.NotAccepted(t.Err) {
+			if err := d.ledger.Release(reservation); err != nil {
+				d.logger.Printf("deployment %s: releasing budget of %s: %s", id, amount, err)
+			}
+		}
+		return t
+	}
+	t.Amount = amount
+
+	return t
+}
+
+// reserve records amount, reduced to the budget left today, in the ledger
+// before it is deposited, or returns the reason no deposit can be made.
+func (d *Daemon) reserve(amount sdktypes.Coin) (sdktypes.Coin, spend.Reservation, string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	amount, skipped := d.withinBudget(amount)
+	if skipped != "" {
+		return amount, spend.Reservation{}, skipped, nil
+	}
+
+	r, err := d.ledger.Add(ledgerKey, sdktypes.NewCoins(amount))
+	return amount, r, "", err
+}
+
+// amount returns the deposit which brings the runway r up to the target.
+func (d *Daemon) amount(r deployment.Runway) sdktypes.Coin {
+	if r.BlockTime <= 0 {
+		return sdktypes.NewInt64Coin(r.BurnRate.Denom, 0)
+	}
+	blocks := int64(d.target / r.BlockTime)
+	needed := r.BurnRate.Amount.MulInt64(blocks).Sub(r.Balance.Amount)
+	if !needed.IsPositive() {
+		return sdktypes.NewInt64Coin(r.BurnRate.Denom, 0)
+	}
+	return sdktypes.NewCoin(r.BurnRate.Denom, needed.Ceil().TruncateInt())
+}
+
+// withinBudget returns amount reduced to the budget left today, or the reason
+// no deposit can be made. It must be called with d.mu held.
+func (d *Daemon) withinBudget(amount sdktypes.Coin) (sdktypes.Coin, string) {
+	if d.budget.Empty() {
+		return amount, ""
+	}
+
+	budget := d.budget.AmountOf(amount.Denom)
+	left := budget.Sub(d.ledger.Spent(ledgerKey).AmountOf(amount.Denom))
+	if !left.IsPositive() {
+		return amount, fmt.Sprintf("daily budget of %s%s spent", budget, amount.Denom)
+	}
+	if left.LT(amount.Amount) {
+		amount.Amount = left
+	}
+
+	return amount, ""
+}
+
+func (d *Daemon) report(t TopUp) {
+	switch {
+	case t.Err != nil && t.ID.Owner == "":
+		d.logger.Printf("listing deployments: %s", t.Err)
+	case t.Err != nil:
+		d.logger.Printf("deployment %s: %s", t.ID, t.Err)
+	case t.Skipped != "":
+		d.logger.Printf("deployment %s: runway %s below %s, deposit skipped: %s", t.ID, t.Runway.Remaining, d.threshold, t.Skipped)
+	case t.Amount.IsPositive():
+		d.logger.Printf("deployment %s: runway %s below %s, deposited %s", t.ID, t.Runway.Remaining, d.threshold, t.Amount)
+	}
+}
+
+func containsID(ids []v1beta2.DeploymentID, id v1beta2.DeploymentID) bool {
+	for _, i := range ids {
+		if i.Equals(id) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,97 +0,0 @@
-package utils
-
-import (
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"net/http"
-	"time"
-)
-
-type Block struct {
-	BlockID struct {
-		Hash  string `json:"hash"`
-		Parts struct {
-			Total int    `json:"total"`
-			Hash  string `json:"hash"`
-		} `json:"parts"`
-	} `json:"block_id"`
-	Block struct {
-		Header struct {
-			Version struct {
-				Block string `json:"block"`
-			} `json:"version"`
-			ChainID     string    `json:"chain_id"`
-			Height      string    `json:"height"`
-			Time        time.Time `json:"time"`
-			LastBlockID struct {
-				Hash  string `json:"hash"`
-				Parts struct {
-					Total int    `json:"total"`
-					Hash  string `json:"hash"`
-				} `json:"parts"`
-			} `json:"last_block_id"`
-			LastCommitHash     string `json:"last_commit_hash"`
-			DataHash           string `json:"data_hash"`
-			ValidatorsHash     string `json:"validators_hash"`
-			NextValidatorsHash string `json:"next_validators_hash"`
-			ConsensusHash      string `json:"consensus_hash"`
-			AppHash            string `json:"app_hash"`
-			LastResultsHash    string `json:"last_results_hash"`
-			EvidenceHash       string `json:"evidence_hash"`
-			ProposerAddress    string `json:"proposer_address"`
-		} `json:"header"`
-		Data struct {
-			Txs []interface{} `json:"txs"`
-		} `json:"data"`
-		Evidence struct {
-			Evidence []interface{} `json:"evidence"`
-		} `json:"evidence"`
-		LastCommit struct {
-			Height  string `json:"height"`
-			Round   int    `json:"round"`
-			BlockID struct {
-				Hash  string `json:"hash"`
-				Parts struct {
-					Total int    `json:"total"`
-					Hash  string `json:"hash"`
-				} `json:"parts"`
-			} `json:"block_id"`
-			Signatures []struct {
-				BlockIDFlag      int       `json:"block_id_flag"`
-				ValidatorAddress string    `json:"validator_address"`
-				Timestamp        time.Time `json:"timestamp"`
-				Signature        string    `json:"signature"`
-			} `json:"signatures"`
-		} `json:"last_commit"`
-	} `json:"block"`
-}
-
-func BlockHeight() string {
-
-	url := "https://akash-api.polkachu.com/blocks/latest"
-	method := "GET"
-
-	client := &http.Client{}
-	req, err := http.NewRequest(method, url, nil)
-
-	if err != nil {
-		fmt.Println(err)
-	}
-	res, err := client.Do(req)
-	if err != nil {
-		fmt.Println(err)
-	}
-	defer res.Body.Close()
-
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	block := Block{}
-
-	json.Unmarshal(body, &block)
-
-	return block.Block.Header.Height
-}